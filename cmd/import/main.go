@@ -2,23 +2,53 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
 
+	"github.com/google/uuid"
 	"github.com/shiftregister-vg/card-craft/internal/cards"
 	"github.com/shiftregister-vg/card-craft/internal/config"
 	"github.com/shiftregister-vg/card-craft/internal/database"
+	"github.com/shiftregister-vg/card-craft/internal/jobs"
 )
 
 func main() {
 	// Parse command line flags
-	gameType := flag.String("game", "", "Game type to import (pokemon, lorcana, starwars, mtg)")
-	clearStatus := flag.Bool("clear-status", false, "Clear the import status before running the import")
+	gameType := flag.String("game", "", fmt.Sprintf("Game type to import (%s), or all", strings.Join(cards.DefaultRegistry.Games(), ", ")))
+	setID := flag.String("set", "", "Import a single set instead of the whole catalog, for games that support it")
+	fromFile := flag.String("from-file", "", "Import from a local card dump instead of the live API")
+	sourceDir := flag.String("source-dir", "", "Import from the card dumps in a directory, named <game>.json")
+	clearStatus := flag.Bool("clear-status", false, "Clear the import status, checkpoint and set syncs before running the import")
 	flag.Parse()
 
 	if *gameType == "" {
 		log.Fatal("Game type is required")
 	}
+	if *fromFile != "" && *sourceDir != "" {
+		log.Fatal("Use either -from-file or -source-dir, not both")
+	}
+
+	// Look up the importers for the requested game
+	var registrations []*cards.ImporterRegistration
+	if *gameType == "all" {
+		if *fromFile != "" || *setID != "" {
+			log.Fatal("-from-file and -set need a single game")
+		}
+		registrations = cards.DefaultRegistry.All()
+	} else {
+		registration, ok := cards.DefaultRegistry.Lookup(*gameType)
+		if !ok {
+			log.Fatalf("Unsupported game type: %s (supported: %s)", *gameType, strings.Join(cards.DefaultRegistry.Games(), ", "))
+		}
+		registrations = append(registrations, registration)
+	}
 
 	// Load configuration
 	cfg, err := config.Load()
@@ -33,39 +63,113 @@ func main() {
 	}
 	defer db.Close()
 
-	// Clear import status if requested
+	// Clear import status if requested, holding each game's import lock so a running import
+	// does not lose its checkpoint halfway through
 	if *clearStatus {
-		if _, err := db.DB.Exec("DELETE FROM mtg_import_status WHERE id = 1"); err != nil {
-			log.Fatalf("Failed to clear import status: %v", err)
+		checkpoints := cards.NewCheckpointStore(db.DB)
+		setSyncs := cards.NewSetSyncStore(db.DB)
+		for _, registration := range registrations {
+			lock, err := database.TryAdvisoryLock(context.Background(), db.DB, "card-import:"+registration.Game)
+			if err != nil {
+				log.Fatalf("Failed to lock %s import: %v", registration.DisplayName, err)
+			}
+			if lock == nil {
+				log.Fatalf("Cannot clear the %s import status while an import of it is running", registration.DisplayName)
+			}
+
+			if registration.Game == "mtg" {
+				if _, err := db.DB.Exec("DELETE FROM mtg_import_status WHERE id = 1"); err != nil {
+					log.Fatalf("Failed to clear import status: %v", err)
+				}
+			}
+			if err := checkpoints.Clear(context.Background(), registration.Game); err != nil {
+				log.Fatalf("Failed to clear import checkpoint: %v", err)
+			}
+			if err := setSyncs.Clear(context.Background(), registration.Game); err != nil {
+				log.Fatalf("Failed to clear set syncs: %v", err)
+			}
+			if err := lock.Release(); err != nil {
+				log.Fatalf("Failed to unlock %s import: %v", registration.DisplayName, err)
+			}
 		}
 		log.Println("Import status cleared")
 	}
 
 	// Create card store
 	cardStore := cards.NewCardStore(db.DB)
-	mtgCardStore := cards.NewMTGCardStore(db.DB)
-
-	// Create game-specific stores
-	pokemonStore := cards.NewPokemonCardStore(db.DB)
-
-	// Select importer based on game type
-	var importer cards.Importer
-	switch *gameType {
-	case "pokemon":
-		importer = cards.NewPokemonImporter(cardStore, pokemonStore)
-	case "lorcana":
-		importer = cards.NewLorcanaImporter(cardStore)
-	case "starwars":
-		importer = cards.NewStarWarsImporter(cardStore)
-	case "mtg":
-		importer = cards.NewMTGImporter(cardStore, mtgCardStore)
-	default:
-		log.Fatalf("Unsupported game type: %s", *gameType)
-	}
+	jobService := jobs.NewService(db.DB)
+
+	// Stop the import cleanly on Ctrl+C or SIGTERM. Batches that were already committed
+	// are kept, and importers that checkpoint resume from them on the next run.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for _, registration := range registrations {
+		// Select the data to import: a local dump, or the live API
+		var source cards.DataSource
+		switch {
+		case *fromFile != "":
+			source = cards.NewFileSource(*fromFile)
+		case *sourceDir != "":
+			path := filepath.Join(*sourceDir, cards.DumpFileName(registration.Game))
+			if _, err := os.Stat(path); err != nil {
+				if *gameType == "all" {
+					log.Printf("Skipping %s: no card dump at %s", registration.DisplayName, path)
+					continue
+				}
+				log.Fatalf("No card dump for %s: %v", registration.DisplayName, err)
+			}
+			source = cards.NewFileSource(path)
+		}
+
+		var importer cards.CardImporter
+		if source != nil {
+			importer, err = cards.DefaultRegistry.NewImporterWithSource(registration.Game, cardStore, db.DB, source)
+			if err != nil {
+				log.Fatalf("Failed to create importer: %v", err)
+			}
+			log.Printf("Importing %s from %s", registration.DisplayName, source.Name())
+		} else {
+			importer = registration.New(cardStore, db.DB)
+		}
+
+		if err := cards.CheckConfigured(importer); err != nil {
+			if *gameType == "all" {
+				log.Printf("Skipping %s: %v", registration.DisplayName, err)
+				continue
+			}
+			log.Fatalf("Cannot import %s: %v", registration.DisplayName, err)
+		}
+
+		// Select the import to run
+		runImport := func(ctx context.Context) error {
+			return importer.Import(ctx, cardStore)
+		}
+		if *setID != "" {
+			setImporter, ok := importer.(cards.SetImporter)
+			if !ok || !registration.HasCapability(cards.CapabilitySingleSet) {
+				log.Fatalf("%s importer does not support importing a single set", registration.DisplayName)
+			}
+			runImport = func(ctx context.Context) error {
+				return setImporter.ImportSet(ctx, *setID)
+			}
+		}
+
+		// Run import, recording it as an import job. The job service holds the game's import
+		// lock, so this never runs alongside a scheduled or API import of the same game.
+		job, err := jobService.Run(ctx, registration.Game, jobs.TriggerCLI, uuid.Nil, runImport)
+		if errors.Is(err, jobs.ErrImportRunning) {
+			log.Fatalf("A %s import is already running; try again once it finishes", registration.DisplayName)
+		}
+		if job != nil && errors.Is(err, context.Canceled) {
+			log.Fatalf("Import job %s cancelled after %d cards; rerun the import to resume", job.ID, job.CardsProcessed)
+		}
+		if err != nil {
+			log.Fatalf("%s import failed: %v", registration.DisplayName, err)
+		}
 
-	// Run import
-	if err := importer.Import(context.Background(), cardStore); err != nil {
-		log.Fatalf("Import failed: %v", err)
+		log.Printf("Import job %s: %d cards processed (%d inserted, %d updated, %d skipped, %d errors)",
+			job.ID, job.CardsProcessed, job.CardsInserted, job.CardsUpdated, job.CardsSkipped, job.ErrorCount)
 	}
 
 	log.Printf("Import completed successfully")
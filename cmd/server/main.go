@@ -2,9 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
@@ -12,6 +17,7 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler/lru"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/gorilla/websocket"
 	"github.com/vektah/gqlparser/v2/ast"
 
 	"github.com/shiftregister-vg/card-craft/internal/auth"
@@ -20,11 +26,15 @@ import (
 	"github.com/shiftregister-vg/card-craft/internal/database"
 	"github.com/shiftregister-vg/card-craft/internal/graph"
 	"github.com/shiftregister-vg/card-craft/internal/graph/generated"
+	"github.com/shiftregister-vg/card-craft/internal/jobs"
 	"github.com/shiftregister-vg/card-craft/internal/middleware"
 	"github.com/shiftregister-vg/card-craft/internal/models"
 	"github.com/shiftregister-vg/card-craft/internal/scheduler"
 )
 
+// shutdownTimeout is how long running requests and card imports get to finish at shutdown
+const shutdownTimeout = 30 * time.Second
+
 func main() {
 	// Load configuration
 	cfg, err := config.Load()
@@ -58,16 +68,25 @@ func main() {
 	authService := auth.NewService(cfg.JWTSecret, userStore)
 
 	// Initialize and start the scheduler for card imports
-	pokemonImporter := cards.NewPokemonImporter(cardStore, pokemonStore)
-	lorcanaImporter := cards.NewLorcanaImporter(cardStore)
-	starWarsImporter := cards.NewStarWarsImporter(cardStore)
-	mtgImporter := cards.NewMTGImporter(cardStore, mtgCardStore)
+	importers := cards.DefaultRegistry.NewImporters(cardStore, db.DB)
+
+	jobService := jobs.NewService(db.DB)
 
-	sched := scheduler.NewScheduler(cardStore, pokemonImporter, lorcanaImporter, starWarsImporter, mtgImporter)
+	// The scheduler is only built when it runs, so a bad schedule cannot stop the server
+	// from starting with imports disabled
+	var sched *scheduler.Scheduler
 	if cfg.EnableCardImports {
+		sched, err = scheduler.NewScheduler(cardStore, jobService, scheduler.Options{
+			DefaultSchedule: cfg.ImportSchedule,
+			Schedules:       cfg.ImportSchedules,
+			Jitter:          cfg.ImportJitter,
+			MaxRuntime:      cfg.ImportMaxRuntime,
+		}, importers...)
+		if err != nil {
+			log.Fatalf("Error creating scheduler: %v", err)
+		}
 		log.Println("Card imports are enabled, starting scheduler...")
 		sched.Start()
-		defer sched.Stop()
 	} else {
 		log.Println("Card imports are disabled, scheduler will not run")
 	}
@@ -82,16 +101,29 @@ func main() {
 			deckStore,
 			collectionStore,
 			authService,
+			jobService,
 		),
 	})
 
 	// Create GraphQL handler with recommended configuration
 	graphqlHandler := handler.New(schema)
+	graphqlHandler.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		Upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				return true // Allow all origins in development
+			},
+		},
+		InitFunc: authMiddleware.WebsocketInit,
+	})
 	graphqlHandler.AddTransport(transport.Options{})
 	graphqlHandler.AddTransport(transport.GET{})
 	graphqlHandler.AddTransport(transport.POST{})
 	graphqlHandler.AddTransport(transport.MultipartForm{})
 
+	// Report GraphQL error codes in the extensions of each error
+	graphqlHandler.SetErrorPresenter(graph.ErrorPresenter)
+
 	// Configure query caching
 	graphqlHandler.SetQueryCache(lru.New[*ast.QueryDocument](1000))
 
@@ -114,8 +146,32 @@ func main() {
 	http.Handle("/query", rateLimitMiddleware.Middleware(authMiddleware.Middleware(graphqlHandler)))
 
 	// Start server
-	log.Printf("Server is running on http://localhost:%s", cfg.Port)
-	if err := http.ListenAndServe(":"+cfg.Port, nil); err != nil {
-		log.Fatalf("Error starting server: %v", err)
+	server := &http.Server{Addr: ":" + cfg.Port}
+	go func() {
+		log.Printf("Server is running on http://localhost:%s", cfg.Port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Error starting server: %v", err)
+		}
+	}()
+
+	// Shut down on Ctrl+C or SIGTERM, letting running requests and card imports finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	log.Println("Shutting down...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error shutting down server: %v", err)
+	}
+	if sched != nil {
+		err = sched.Stop(shutdownCtx)
+	} else {
+		err = jobService.Shutdown(shutdownCtx)
+	}
+	if err != nil {
+		log.Printf("Card imports did not finish before shutdown: %v", err)
 	}
 }
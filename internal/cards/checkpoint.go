@@ -0,0 +1,79 @@
+package cards
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Checkpoint records how far an interrupted import got, so a later run can resume from it
+type Checkpoint struct {
+	Game string `json:"game"`
+	// Source identifies the data the batches were built from. A checkpoint only applies
+	// to a run reading the same source.
+	Source           string    `json:"source"`
+	BatchesCompleted int       `json:"batchesCompleted"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+// CheckpointStore handles database operations for import checkpoints
+type CheckpointStore struct {
+	db *sql.DB
+}
+
+// NewCheckpointStore creates a new checkpoint store
+func NewCheckpointStore(db *sql.DB) *CheckpointStore {
+	return &CheckpointStore{db: db}
+}
+
+// Get returns the checkpoint of a game, or nil if it has none
+func (s *CheckpointStore) Get(ctx context.Context, game string) (*Checkpoint, error) {
+	query := `
+		SELECT game, source, batches_completed, updated_at
+		FROM import_checkpoints
+		WHERE game = $1
+	`
+
+	var checkpoint Checkpoint
+	err := s.db.QueryRowContext(ctx, query, game).Scan(
+		&checkpoint.Game,
+		&checkpoint.Source,
+		&checkpoint.BatchesCompleted,
+		&checkpoint.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import checkpoint: %w", err)
+	}
+
+	return &checkpoint, nil
+}
+
+// Save records that every batch before batchesCompleted has been committed
+func (s *CheckpointStore) Save(ctx context.Context, game, source string, batchesCompleted int) error {
+	query := `
+		INSERT INTO import_checkpoints (game, source, batches_completed)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (game) DO UPDATE SET
+			source = EXCLUDED.source,
+			batches_completed = EXCLUDED.batches_completed
+	`
+
+	if _, err := s.db.ExecContext(ctx, query, game, source, batchesCompleted); err != nil {
+		return fmt.Errorf("failed to save import checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// Clear removes the checkpoint of a game once its import has completed
+func (s *CheckpointStore) Clear(ctx context.Context, game string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM import_checkpoints WHERE game = $1`, game); err != nil {
+		return fmt.Errorf("failed to clear import checkpoint: %w", err)
+	}
+
+	return nil
+}
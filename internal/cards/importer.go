@@ -2,10 +2,12 @@ package cards
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/shiftregister-vg/card-craft/internal/types"
+	"github.com/shiftregister-vg/card-craft/internal/database"
+	"github.com/shiftregister-vg/card-craft/internal/models"
 )
 
 // CardImporter defines the interface for importing cards
@@ -39,8 +41,9 @@ func (i *BaseImporter) GetGame() string {
 }
 
 // CreateOrUpdateCard handles the common logic for creating or updating a card
-func (i *BaseImporter) CreateOrUpdateCard(card *types.Card) error {
-	existing, err := i.store.FindByGameAndNumber(i.game, card.SetCode, card.Number)
+// and reports the outcome to the progress reporter of the context
+func (i *BaseImporter) CreateOrUpdateCard(ctx context.Context, card *models.Card) error {
+	existing, err := i.store.FindByGameAndNumber(ctx, i.game, card.SetCode, card.Number)
 	if err != nil {
 		return err
 	}
@@ -48,16 +51,58 @@ func (i *BaseImporter) CreateOrUpdateCard(card *types.Card) error {
 	if existing != nil {
 		card.ID = existing.ID
 		card.CreatedAt = existing.CreatedAt
-		return i.store.Update(card)
+		if err := i.store.Update(ctx, card); err != nil {
+			return err
+		}
+		ProgressFromContext(ctx).AddCards(0, 1, 0)
+		return nil
 	}
 
-	return i.store.Create(card)
+	if err := i.store.Create(ctx, card); err != nil {
+		return err
+	}
+	ProgressFromContext(ctx).AddCards(1, 0, 0)
+	return nil
+}
+
+// CreateOrUpdateCardWithDetails creates or updates a card like CreateOrUpdateCard, and saves
+// its game details with saveDetails in the same transaction, so a card is never left without them
+func (i *BaseImporter) CreateOrUpdateCardWithDetails(ctx context.Context, card *models.Card, saveDetails func(tx *sql.Tx) error) error {
+	existing, err := i.store.FindByGameAndNumber(ctx, i.game, card.SetCode, card.Number)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		card.ID = existing.ID
+		card.CreatedAt = existing.CreatedAt
+	}
+
+	err = database.WithTransaction(ctx, i.store.db, func(tx *database.Transaction) error {
+		save := i.store.CreateBatch
+		if existing != nil {
+			save = i.store.UpdateBatch
+		}
+		if err := save(ctx, tx.Tx(), []*models.Card{card}); err != nil {
+			return err
+		}
+		return saveDetails(tx.Tx())
+	})
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		ProgressFromContext(ctx).AddCards(0, 1, 0)
+	} else {
+		ProgressFromContext(ctx).AddCards(1, 0, 0)
+	}
+	return nil
 }
 
 // NewCard creates a new card with common fields set
-func (i *BaseImporter) NewCard() *types.Card {
+func (i *BaseImporter) NewCard() *models.Card {
 	now := time.Now()
-	return &types.Card{
+	return &models.Card{
 		ID:        uuid.New(),
 		Game:      i.game,
 		CreatedAt: now,
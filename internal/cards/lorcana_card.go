@@ -0,0 +1,166 @@
+package cards
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// LorcanaCard represents Lorcana-specific card data
+type LorcanaCard struct {
+	ID              string           `json:"id"`
+	CardID          string           `json:"card_id"`
+	Version         string           `json:"version"`
+	InkColor        string           `json:"inkColor"`
+	Inkable         bool             `json:"inkable"`
+	CardType        string           `json:"cardType"`
+	Cost            int              `json:"cost"`
+	Strength        *int             `json:"strength"`
+	Willpower       *int             `json:"willpower"`
+	Lore            *int             `json:"lore"`
+	MoveCost        *int             `json:"moveCost"`
+	Classifications []string         `json:"classifications"`
+	Abilities       []LorcanaAbility `json:"abilities"`
+	BodyText        string           `json:"bodyText"`
+	FlavorText      string           `json:"flavorText"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+// LorcanaAbility represents a keyword, triggered, activated or static ability on a Lorcana card
+type LorcanaAbility struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Effect   string `json:"effect"`
+	FullText string `json:"fullText"`
+}
+
+// LorcanaCardStore handles database operations for Lorcana-specific card data
+type LorcanaCardStore struct {
+	db *sql.DB
+}
+
+// NewLorcanaCardStore creates a new Lorcana card store
+func NewLorcanaCardStore(db *sql.DB) *LorcanaCardStore {
+	return &LorcanaCardStore{db: db}
+}
+
+// Upsert creates the Lorcana details for a card or replaces the existing ones, within the
+// transaction that saves the card
+func (s *LorcanaCardStore) Upsert(ctx context.Context, tx *sql.Tx, card *LorcanaCard) error {
+	abilitiesJSON, err := json.Marshal(card.Abilities)
+	if err != nil {
+		return fmt.Errorf("failed to marshal abilities: %w", err)
+	}
+
+	query := `
+		INSERT INTO lorcana_cards (
+			card_id, version, ink_color, inkable, card_type, cost, strength,
+			willpower, lore, move_cost, classifications, abilities, body_text, flavor_text
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (card_id) DO UPDATE SET
+			version = EXCLUDED.version,
+			ink_color = EXCLUDED.ink_color,
+			inkable = EXCLUDED.inkable,
+			card_type = EXCLUDED.card_type,
+			cost = EXCLUDED.cost,
+			strength = EXCLUDED.strength,
+			willpower = EXCLUDED.willpower,
+			lore = EXCLUDED.lore,
+			move_cost = EXCLUDED.move_cost,
+			classifications = EXCLUDED.classifications,
+			abilities = EXCLUDED.abilities,
+			body_text = EXCLUDED.body_text,
+			flavor_text = EXCLUDED.flavor_text
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(ctx, query,
+		card.CardID,
+		card.Version,
+		card.InkColor,
+		card.Inkable,
+		card.CardType,
+		card.Cost,
+		card.Strength,
+		card.Willpower,
+		card.Lore,
+		card.MoveCost,
+		pq.Array(card.Classifications),
+		abilitiesJSON,
+		card.BodyText,
+		card.FlavorText,
+	).Scan(&card.ID, &card.CreatedAt, &card.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert lorcana card: %w", err)
+	}
+
+	return nil
+}
+
+// FindByCardID finds the Lorcana details of a card by its card ID
+func (s *LorcanaCardStore) FindByCardID(ctx context.Context, cardID string) (*LorcanaCard, error) {
+	query := `
+		SELECT id, card_id, COALESCE(version, ''), ink_color, inkable, card_type, cost,
+			strength, willpower, lore, move_cost, classifications, abilities,
+			COALESCE(body_text, ''), COALESCE(flavor_text, ''), created_at, updated_at
+		FROM lorcana_cards
+		WHERE card_id = $1
+	`
+
+	var card LorcanaCard
+	var strength, willpower, lore, moveCost sql.NullInt64
+	var abilitiesJSON []byte
+
+	err := s.db.QueryRowContext(ctx, query, cardID).Scan(
+		&card.ID,
+		&card.CardID,
+		&card.Version,
+		&card.InkColor,
+		&card.Inkable,
+		&card.CardType,
+		&card.Cost,
+		&strength,
+		&willpower,
+		&lore,
+		&moveCost,
+		pq.Array(&card.Classifications),
+		&abilitiesJSON,
+		&card.BodyText,
+		&card.FlavorText,
+		&card.CreatedAt,
+		&card.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find lorcana card: %w", err)
+	}
+
+	card.Strength = nullIntPtr(strength)
+	card.Willpower = nullIntPtr(willpower)
+	card.Lore = nullIntPtr(lore)
+	card.MoveCost = nullIntPtr(moveCost)
+
+	if abilitiesJSON != nil {
+		if err := json.Unmarshal(abilitiesJSON, &card.Abilities); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal abilities: %w", err)
+		}
+	}
+
+	return &card, nil
+}
+
+// nullIntPtr converts a nullable integer column to an int pointer
+func nullIntPtr(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	v := int(n.Int64)
+	return &v
+}
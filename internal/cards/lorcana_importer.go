@@ -2,17 +2,51 @@ package cards
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
 )
 
+// LorcanaDataURL is the published LorcanaJSON dump containing every English Lorcana card
+const LorcanaDataURL = "https://lorcanajson.org/files/current/en/allCards.json"
+
+func init() {
+	Register(ImporterRegistration{
+		Game:         "lorcana",
+		DisplayName:  "Disney Lorcana",
+		DetailTable:  "lorcana_cards",
+		Capabilities: []Capability{CapabilityFullImport, CapabilitySingleSet},
+		New: func(store *CardStore, db *sql.DB) CardImporter {
+			return NewLorcanaImporter(store, NewLorcanaCardStore(db))
+		},
+		NewWithSource: func(store *CardStore, db *sql.DB, source DataSource) CardImporter {
+			return NewLorcanaImporterWithSource(store, NewLorcanaCardStore(db), source)
+		},
+	})
+}
+
 // LorcanaImporter handles importing Lorcana card data
 type LorcanaImporter struct {
 	*BaseImporter
+	lorcanaStore *LorcanaCardStore
+	source       DataSource
 }
 
-// NewLorcanaImporter creates a new Lorcana importer
-func NewLorcanaImporter(store *CardStore) *LorcanaImporter {
+// NewLorcanaImporter creates a new Lorcana importer that reads the published LorcanaJSON dump
+func NewLorcanaImporter(store *CardStore, lorcanaStore *LorcanaCardStore) *LorcanaImporter {
+	return NewLorcanaImporterWithSource(store, lorcanaStore, NewHTTPSource(LorcanaDataURL))
+}
+
+// NewLorcanaImporterWithSource creates a new Lorcana importer that reads a LorcanaJSON dump from the given source
+func NewLorcanaImporterWithSource(store *CardStore, lorcanaStore *LorcanaCardStore, source DataSource) *LorcanaImporter {
 	return &LorcanaImporter{
 		BaseImporter: NewBaseImporter(store, "lorcana"),
+		lorcanaStore: lorcanaStore,
+		source:       source,
 	}
 }
 
@@ -23,14 +57,192 @@ func (i *LorcanaImporter) Import(ctx context.Context, store *CardStore) error {
 
 // ImportSet imports all cards from a specific set
 func (i *LorcanaImporter) ImportSet(ctx context.Context, setID string) error {
-	// TODO: Implement Lorcana set import
-	// This will need to be implemented once we have access to the Lorcana API or data source
-	return nil
+	data, err := i.fetchData(ctx)
+	if err != nil {
+		return err
+	}
+
+	set, ok := data.Sets[setID]
+	if !ok {
+		return fmt.Errorf("lorcana set %s not found in card data", setID)
+	}
+
+	return i.importSet(ctx, setID, set, cardsBySet(data)[setID])
 }
 
-// ImportLatestSets imports cards from the latest Lorcana sets
+// ImportLatestSets imports cards from every set in the Lorcana card data
 func (i *LorcanaImporter) ImportLatestSets(ctx context.Context) error {
-	// TODO: Implement Lorcana latest sets import
-	// This will need to be implemented once we have access to the Lorcana API or data source
+	startTime := time.Now()
+	log.Printf("Starting Lorcana card import process")
+
+	data, err := i.fetchData(ctx)
+	if err != nil {
+		return err
+	}
+
+	setCodes := make([]string, 0, len(data.Sets))
+	for code := range data.Sets {
+		setCodes = append(setCodes, code)
+	}
+	sort.Slice(setCodes, func(a, b int) bool {
+		return data.Sets[setCodes[a]].ReleaseDate < data.Sets[setCodes[b]].ReleaseDate
+	})
+
+	log.Printf("Found %d sets to import", len(setCodes))
+
+	bySet := cardsBySet(data)
+	progress := ProgressFromContext(ctx)
+	progress.SetBatches(0, len(setCodes))
+	for setIndex, code := range setCodes {
+		set := data.Sets[code]
+		log.Printf("Importing set %d/%d: %s (%s)", setIndex+1, len(setCodes), set.Name, code)
+		if err := i.importSet(ctx, code, set, bySet[code]); err != nil {
+			return fmt.Errorf("failed to import set %s: %w", code, err)
+		}
+		progress.SetBatches(setIndex+1, len(setCodes))
+	}
+
+	log.Printf("Lorcana import completed in %s", time.Since(startTime))
 	return nil
 }
+
+// fetchData reads and decodes the LorcanaJSON dump from the importer's source
+func (i *LorcanaImporter) fetchData(ctx context.Context) (*LorcanaData, error) {
+	reader, err := i.source.Open(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lorcana card data: %w", err)
+	}
+	defer reader.Close()
+
+	var data LorcanaData
+	if err := json.NewDecoder(reader).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode lorcana card data: %w", err)
+	}
+
+	return &data, nil
+}
+
+// cardsBySet groups the cards of a LorcanaJSON dump by set code
+func cardsBySet(data *LorcanaData) map[string][]LorcanaAPICard {
+	bySet := make(map[string][]LorcanaAPICard)
+	for _, apiCard := range data.Cards {
+		bySet[apiCard.SetCode] = append(bySet[apiCard.SetCode], apiCard)
+	}
+	return bySet
+}
+
+// lorcanaNumber returns the collector number of a card. Variants of a card share its number
+// and are told apart by a letter, such as 4a to 4e for the Dalmatian Puppy cards.
+func lorcanaNumber(apiCard LorcanaAPICard) string {
+	return strconv.Itoa(apiCard.Number) + apiCard.Variant
+}
+
+// importSet creates or updates the cards of one set, each together with its Lorcana details
+func (i *LorcanaImporter) importSet(ctx context.Context, setCode string, set LorcanaSet, apiCards []LorcanaAPICard) error {
+	var total int
+	for _, apiCard := range apiCards {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		card := i.NewCard()
+		card.Name = apiCard.FullName
+		if card.Name == "" {
+			card.Name = apiCard.Name
+		}
+		card.SetCode = setCode
+		card.SetName = set.Name
+		card.Number = lorcanaNumber(apiCard)
+		card.Rarity = apiCard.Rarity
+		card.ImageUrl = apiCard.Images.Full
+
+		abilities := make([]LorcanaAbility, 0, len(apiCard.Abilities))
+		for _, ability := range apiCard.Abilities {
+			name := ability.Name
+			if name == "" {
+				name = ability.Keyword
+			}
+			abilities = append(abilities, LorcanaAbility{
+				Name:     name,
+				Type:     ability.Type,
+				Effect:   ability.Effect,
+				FullText: ability.FullText,
+			})
+		}
+
+		lorcanaCard := &LorcanaCard{
+			Version:         apiCard.Version,
+			InkColor:        apiCard.Color,
+			Inkable:         apiCard.Inkwell,
+			CardType:        apiCard.Type,
+			Cost:            apiCard.Cost,
+			Strength:        apiCard.Strength,
+			Willpower:       apiCard.Willpower,
+			Lore:            apiCard.Lore,
+			MoveCost:        apiCard.MoveCost,
+			Classifications: apiCard.Subtypes,
+			Abilities:       abilities,
+			BodyText:        apiCard.FullText,
+			FlavorText:      apiCard.FlavorText,
+		}
+
+		err := i.CreateOrUpdateCardWithDetails(ctx, card, func(tx *sql.Tx) error {
+			lorcanaCard.CardID = card.ID.String()
+			return i.lorcanaStore.Upsert(ctx, tx, lorcanaCard)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save card %s (%s): %w", card.Name, card.Number, err)
+		}
+
+		total++
+	}
+
+	log.Printf("Set %s import completed: %d cards processed", setCode, total)
+	return nil
+}
+
+// LorcanaData is the top-level structure of a LorcanaJSON card dump
+type LorcanaData struct {
+	Sets  map[string]LorcanaSet `json:"sets"`
+	Cards []LorcanaAPICard      `json:"cards"`
+}
+
+type LorcanaSet struct {
+	Name        string `json:"name"`
+	Number      int    `json:"number"`
+	Type        string `json:"type"`
+	ReleaseDate string `json:"releaseDate"`
+}
+
+type LorcanaAPICard struct {
+	ID         int      `json:"id"`
+	Name       string   `json:"name"`
+	Version    string   `json:"version"`
+	FullName   string   `json:"fullName"`
+	SetCode    string   `json:"setCode"`
+	Number     int      `json:"number"`
+	Variant    string   `json:"variant"` // Set on cards sharing a number, such as "a" for 4a
+	Rarity     string   `json:"rarity"`
+	Color      string   `json:"color"`
+	Inkwell    bool     `json:"inkwell"`
+	Type       string   `json:"type"`
+	Cost       int      `json:"cost"`
+	Strength   *int     `json:"strength"`
+	Willpower  *int     `json:"willpower"`
+	Lore       *int     `json:"lore"`
+	MoveCost   *int     `json:"moveCost"`
+	Subtypes   []string `json:"subtypes"`
+	FullText   string   `json:"fullText"`
+	FlavorText string   `json:"flavorText"`
+	Abilities  []struct {
+		Name     string `json:"name"`
+		Keyword  string `json:"keyword"`
+		Type     string `json:"type"`
+		Effect   string `json:"effect"`
+		FullText string `json:"fullText"`
+	} `json:"abilities"`
+	Images struct {
+		Full      string `json:"full"`
+		Thumbnail string `json:"thumbnail"`
+	} `json:"images"`
+}
@@ -0,0 +1,83 @@
+package cards
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// lorcanaFixture is a LorcanaJSON dump with the variants of a card sharing its number
+const lorcanaFixture = `{
+	"sets": {
+		"3": {"name": "Into the Inklands", "number": 3, "type": "expansion", "releaseDate": "2024-02-23"},
+		"1": {"name": "The First Chapter", "number": 1, "type": "expansion", "releaseDate": "2023-08-18"}
+	},
+	"cards": [
+		{"id": 1, "name": "Ariel", "version": "On Human Legs", "fullName": "Ariel - On Human Legs", "setCode": "1", "number": 1, "rarity": "Uncommon", "color": "Amber", "type": "Character", "cost": 4},
+		{"id": 2, "name": "Dalmatian Puppy", "version": "Tail Wagger", "fullName": "Dalmatian Puppy - Tail Wagger", "setCode": "3", "number": 4, "variant": "a", "rarity": "Common", "color": "Amber", "type": "Character", "cost": 1},
+		{"id": 3, "name": "Dalmatian Puppy", "version": "Tail Wagger", "fullName": "Dalmatian Puppy - Tail Wagger", "setCode": "3", "number": 4, "variant": "b", "rarity": "Common", "color": "Amber", "type": "Character", "cost": 1},
+		{"id": 4, "name": "Dalmatian Puppy", "version": "Tail Wagger", "fullName": "Dalmatian Puppy - Tail Wagger", "setCode": "3", "number": 4, "variant": "c", "rarity": "Common", "color": "Amber", "type": "Character", "cost": 1},
+		{"id": 5, "name": "Pongo", "version": "Determined Father", "fullName": "Pongo - Determined Father", "setCode": "3", "number": 5, "rarity": "Rare", "color": "Amber", "type": "Character", "cost": 4}
+	]
+}`
+
+func TestLorcanaCardsBySetKeepsVariantsApart(t *testing.T) {
+	var data LorcanaData
+	if err := json.Unmarshal([]byte(lorcanaFixture), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	bySet := cardsBySet(&data)
+	numbers := make(map[string][]string)
+	for code, apiCards := range bySet {
+		for _, apiCard := range apiCards {
+			numbers[code] = append(numbers[code], lorcanaNumber(apiCard))
+		}
+	}
+
+	want := map[string][]string{"1": {"1"}, "3": {"4a", "4b", "4c", "5"}}
+	if !reflect.DeepEqual(numbers, want) {
+		t.Errorf("numbers by set = %v, want %v", numbers, want)
+	}
+}
+
+func TestLorcanaImporterStoresEachVariant(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	path := filepath.Join(t.TempDir(), "allCards.json")
+	if err := os.WriteFile(path, []byte(lorcanaFixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewCardStore(db)
+	lorcanaStore := NewLorcanaCardStore(db)
+	importer := NewLorcanaImporterWithSource(store, lorcanaStore, NewFileSource(path))
+
+	// A second import updates the cards in place
+	for run := 0; run < 2; run++ {
+		if err := importer.Import(ctx, store); err != nil {
+			t.Fatalf("import %d: %v", run+1, err)
+		}
+	}
+
+	cards, err := store.FindBySet(ctx, "lorcana", "3")
+	if err != nil {
+		t.Fatalf("FindBySet(): %v", err)
+	}
+	var numbers []string
+	for _, card := range cards {
+		numbers = append(numbers, card.Number)
+
+		details, err := lorcanaStore.FindByCardID(ctx, card.ID.String())
+		if err != nil || details == nil {
+			t.Errorf("card %s has no Lorcana details: %v", card.Number, err)
+		}
+	}
+	if want := []string{"4a", "4b", "4c", "5"}; !reflect.DeepEqual(numbers, want) {
+		t.Errorf("set 3 has cards %v, want %v", numbers, want)
+	}
+}
@@ -2,31 +2,52 @@ package cards
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"crypto/sha256"
-	"encoding/hex"
-
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"github.com/shiftregister-vg/card-craft/internal/database"
-	"github.com/shiftregister-vg/card-craft/internal/types"
+	"github.com/shiftregister-vg/card-craft/internal/models"
 )
 
+func init() {
+	Register(ImporterRegistration{
+		Game:         "mtg",
+		DisplayName:  "Magic: The Gathering",
+		DetailTable:  "mtg_cards",
+		Capabilities: []Capability{CapabilityFullImport, CapabilityIncremental},
+		New: func(store *CardStore, db *sql.DB) CardImporter {
+			return NewMTGImporter(store, NewMTGCardStore(db))
+		},
+		NewWithSource: func(store *CardStore, db *sql.DB, source DataSource) CardImporter {
+			return NewMTGImporterWithSource(store, NewMTGCardStore(db), source)
+		},
+	})
+}
+
 // MTGImporter handles importing Magic: The Gathering card data
 type MTGImporter struct {
 	cardStore    *CardStore
 	mtgCardStore *MTGCardStore
+	checkpoints  *CheckpointStore
 	client       *http.Client
+	// source holds a local Scryfall bulk data file. When nil, the current
+	// default cards file is downloaded from Scryfall.
+	source DataSource
 }
 
 // NewMTGImporter creates a new MTG importer
@@ -34,12 +55,21 @@ func NewMTGImporter(cardStore *CardStore, mtgCardStore *MTGCardStore) *MTGImport
 	return &MTGImporter{
 		cardStore:    cardStore,
 		mtgCardStore: mtgCardStore,
+		checkpoints:  NewCheckpointStore(cardStore.db),
 		client: &http.Client{
 			Timeout: 5 * time.Minute, // Increased timeout for bulk data download
 		},
 	}
 }
 
+// NewMTGImporterWithSource creates a new MTG importer that reads a Scryfall default cards
+// bulk data file from the given source instead of downloading it
+func NewMTGImporterWithSource(cardStore *CardStore, mtgCardStore *MTGCardStore, source DataSource) *MTGImporter {
+	importer := NewMTGImporter(cardStore, mtgCardStore)
+	importer.source = source
+	return importer
+}
+
 // Import implements the Importer interface
 func (i *MTGImporter) Import(ctx context.Context, store *CardStore) error {
 	return i.ImportBulkData(ctx)
@@ -87,8 +117,8 @@ func (i *MTGImporter) fetchBulkDataInfo(ctx context.Context) (*BulkDataInfo, err
 // downloadBulkData downloads the bulk data file with retries
 func (i *MTGImporter) downloadBulkData(ctx context.Context, downloadURI string) (io.ReadCloser, error) {
 	// Check for cached version first
-	cacheDir := os.Getenv("DEVBOX_PROJECT_ROOT") + "/.devbox/cache"
-	cacheFile := cacheDir + "/mtg_bulk_data.json"
+	cacheDir := bulkDataCacheDir()
+	cacheFile := filepath.Join(cacheDir, "mtg_bulk_data.json")
 
 	// Create cache directory if it doesn't exist
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
@@ -181,17 +211,76 @@ func (i *MTGImporter) downloadBulkData(ctx context.Context, downloadURI string)
 	return nil, fmt.Errorf("failed to download bulk data after %d attempts", maxRetries)
 }
 
+// bulkDataCacheDir returns the directory downloaded bulk data files are cached in:
+// $CARD_CRAFT_CACHE_DIR, the devbox cache of the project, or the user's cache directory
+func bulkDataCacheDir() string {
+	if dir := os.Getenv("CARD_CRAFT_CACHE_DIR"); dir != "" {
+		return dir
+	}
+	if root := os.Getenv("DEVBOX_PROJECT_ROOT"); root != "" {
+		return filepath.Join(root, ".devbox", "cache")
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "card-craft")
+	}
+	return filepath.Join(os.TempDir(), "card-craft")
+}
+
+// openBulkData opens the bulk data file to import and returns what identifies its content,
+// which keys the import checkpoint. Files, including the download cache, are identified by a
+// hash of their content, since a cached file may have been downloaded from another URI than
+// the current one and a local dump may be replaced in place. Streams are identified by name.
+func (i *MTGImporter) openBulkData(ctx context.Context) (io.ReadCloser, string, error) {
+	var reader io.ReadCloser
+	var name string
+	if i.source != nil {
+		var err error
+		if reader, err = i.source.Open(ctx); err != nil {
+			return nil, "", fmt.Errorf("failed to open bulk data: %w", err)
+		}
+		name = i.source.Name()
+	} else {
+		// Get bulk data info
+		info, err := i.fetchBulkDataInfo(ctx)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to fetch bulk data info: %w", err)
+		}
+
+		if reader, err = i.downloadBulkData(ctx, info.DownloadURI); err != nil {
+			return nil, "", fmt.Errorf("failed to download bulk data: %w", err)
+		}
+		name = info.DownloadURI
+	}
+
+	file, ok := reader.(*os.File)
+	if !ok {
+		return reader, name, nil
+	}
+	digest, err := fileDigest(file)
+	if err != nil {
+		file.Close()
+		return nil, "", err
+	}
+	return file, digest, nil
+}
+
+// fileDigest returns the SHA-256 of a file's content and rewinds it for reading
+func fileDigest(file *os.File) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to hash bulk data: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to rewind bulk data: %w", err)
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
 // ImportBulkData imports all cards from the bulk data
 func (i *MTGImporter) ImportBulkData(ctx context.Context) error {
 	startTime := time.Now()
 	log.Printf("Starting MTG card import process")
 
-	// Get bulk data info
-	info, err := i.fetchBulkDataInfo(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to fetch bulk data info: %w", err)
-	}
-
 	// Get the last import timestamp
 	lastImport, err := i.getLastImportTimestamp(ctx)
 	if err != nil {
@@ -200,9 +289,9 @@ func (i *MTGImporter) ImportBulkData(ctx context.Context) error {
 
 	// Download and process the bulk data
 	downloadStart := time.Now()
-	reader, err := i.downloadBulkData(ctx, info.DownloadURI)
+	reader, source, err := i.openBulkData(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to download bulk data: %w", err)
+		return err
 	}
 	defer reader.Close()
 	log.Printf("Downloaded bulk data in %v", time.Since(downloadStart))
@@ -217,148 +306,151 @@ func (i *MTGImporter) ImportBulkData(ctx context.Context) error {
 
 	// Constants for batch processing
 	const (
-		batchSize     = 100
-		numWorkers    = 8 // Optimal number of workers based on typical CPU cores
-		errorChanSize = 100
+		batchSize  = 100
+		numWorkers = 8 // Optimal number of workers based on typical CPU cores
 	)
 
-	// Build all batches first
-	buildStart := time.Now()
-	var batches [][]MTGAPICard
-	var currentBatch []MTGAPICard
-	var processedCards int
-
-	log.Printf("Building batches...")
-	for decoder.More() {
-		var card MTGAPICard
-		if err := decoder.Decode(&card); err != nil {
-			return fmt.Errorf("failed to decode card: %w", err)
-		}
-
-		currentBatch = append(currentBatch, card)
-		processedCards++
-
-		if len(currentBatch) >= batchSize {
-			batches = append(batches, currentBatch)
-			currentBatch = nil
-		}
-
-		// Log progress every 1000 cards
-		if processedCards%1000 == 0 {
-			log.Printf("Built %d cards into %d batches", processedCards, len(batches))
-		}
+	// Resume from the checkpoint of an interrupted run over the same bulk data content.
+	// Batches are cut from the file deterministically, so the first completed ones can be skipped.
+	resumeFrom := 0
+	checkpoint, err := i.checkpoints.Get(ctx, "mtg")
+	if err != nil {
+		return err
 	}
-
-	// Add the final batch if it has any cards
-	if len(currentBatch) > 0 {
-		batches = append(batches, currentBatch)
+	if checkpoint != nil && checkpoint.Source == source {
+		resumeFrom = checkpoint.BatchesCompleted
+		log.Printf("Resuming from checkpoint: %d batches already imported", resumeFrom)
 	}
 
-	buildDuration := time.Since(buildStart)
-	log.Printf("Finished building %d batches with %d total cards in %v",
-		len(batches), processedCards, buildDuration)
-
-	// Create channels for batch processing
-	batchChan := make(chan []MTGAPICard, numWorkers*2)
-	errorChan := make(chan error, errorChanSize)
-	doneChan := make(chan struct{})
-	progressChan := make(chan int, numWorkers*2)                          // Channel for tracking progress
-	statsChan := make(chan struct{ inserted, updated int }, numWorkers*2) // Channel for tracking stats
+	// The batch channel holds only a few batches, so the decoder waits for the workers
+	// instead of reading the whole file into memory
+	batchChan := make(chan mtgBatch, numWorkers)
+	resultChan := make(chan mtgBatchResult, numWorkers)
+	streamChan := make(chan mtgStreamResult, 1)
+	stopFeeding := make(chan struct{})
 
 	// Create a wait group for workers
 	var wg sync.WaitGroup
+	progress := ProgressFromContext(ctx)
 
-	// Start worker goroutines
+	// Start worker goroutines. Each batch is committed in its own transaction, so a cancelled
+	// context rolls back only the batches that are still in flight.
 	for w := 0; w < numWorkers; w++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			batchNum := 0
 			for batch := range batchChan {
-				batchNum++
-				inserted, updated, err := i.processBatch(ctx, batch, lastImport, batchNum)
-				if err != nil {
-					select {
-					case errorChan <- fmt.Errorf("failed to process batch: %w", err):
-					default:
-						// If error channel is full, log and continue
-						log.Printf("Error processing batch: %v", err)
-					}
-				}
-				// Report batch completion and stats
-				select {
-				case progressChan <- 1:
-				default:
-					// If progress channel is full, skip reporting
-				}
-				select {
-				case statsChan <- struct{ inserted, updated int }{inserted, updated}:
-				default:
-					// If stats channel is full, skip reporting
+				inserted, updated, err := i.processBatch(ctx, batch.cards, lastImport, batch.index+1)
+				resultChan <- mtgBatchResult{
+					index:    batch.index,
+					size:     len(batch.cards),
+					inserted: inserted,
+					updated:  updated,
+					err:      err,
 				}
 			}
 		}()
 	}
 
-	// Start a goroutine to close doneChan when all workers are done
+	// Close the result channel once every worker has finished
 	go func() {
 		wg.Wait()
-		close(doneChan)
+		close(resultChan)
 	}()
 
-	// Start a goroutine to track progress and stats
-	var processedBatches int
-	var totalInserted, totalUpdated int
+	// Stream batches from the decoder to the workers until the file ends, the import fails
+	// or it is cancelled
+	processStart := time.Now()
+	log.Printf("Starting batch processing with %d workers...", numWorkers)
+
+	var totalBatches atomic.Int64
 	go func() {
-		for {
-			select {
-			case <-progressChan:
-				processedBatches++
-				if processedBatches%10 == 0 { // Log every 10 batches
-					log.Printf("Processed %d/%d batches (%.1f%%)",
-						processedBatches, len(batches),
-						float64(processedBatches)/float64(len(batches))*100)
-				}
-			case stats := <-statsChan:
-				totalInserted += stats.inserted
-				totalUpdated += stats.updated
-			case <-doneChan:
-				return
-			}
+		defer close(batchChan)
+		cards, batches, err := streamMTGBatches(ctx, decoder, batchSize, resumeFrom, batchChan, stopFeeding)
+		if err == nil {
+			totalBatches.Store(int64(batches))
 		}
+		streamChan <- mtgStreamResult{cards: cards, batches: batches, err: err}
 	}()
 
-	// Start processing batches
-	processStart := time.Now()
-	log.Printf("Starting batch processing with %d workers...", numWorkers)
+	// Collect the results, advancing the checkpoint over the batches committed so far.
+	// Batches finish out of order, so the checkpoint only covers the unbroken run of
+	// completed batches from the start.
+	progress.SetBatches(resumeFrom, 0)
+	processedBatches := resumeFrom
+	nextUncommitted := resumeFrom
+	completed := make(map[int]bool)
+	var totalInserted, totalUpdated int
+	var firstErr error
+	checkpointCtx := context.WithoutCancel(ctx)
 
-	// Send all batches to the workers
-	for _, batch := range batches {
-		select {
-		case batchChan <- batch:
-		case err := <-errorChan:
-			return fmt.Errorf("worker error: %w", err)
-		case <-ctx.Done():
-			return ctx.Err()
+	stopOnError := func(err error) {
+		if firstErr == nil {
+			firstErr = err
+			close(stopFeeding)
 		}
 	}
 
-	// Close the batch channel to signal workers to finish
-	close(batchChan)
+	for result := range resultChan {
+		if result.err != nil {
+			progress.RecordError(result.err)
+			stopOnError(fmt.Errorf("worker error: %w", result.err))
+			continue
+		}
+
+		processedBatches++
+		totalInserted += result.inserted
+		totalUpdated += result.updated
+		progress.AddCards(result.inserted, result.updated, result.size-result.inserted-result.updated)
+
+		// The total is unknown (zero) until the decoder reaches the end of the file
+		total := int(totalBatches.Load())
+		progress.SetBatches(processedBatches, total)
+
+		completed[result.index] = true
+		advanced := false
+		for completed[nextUncommitted] {
+			delete(completed, nextUncommitted)
+			nextUncommitted++
+			advanced = true
+		}
+		if advanced {
+			if err := i.checkpoints.Save(checkpointCtx, "mtg", source, nextUncommitted); err != nil {
+				log.Printf("Error saving import checkpoint: %v", err)
+			}
+		}
+
+		if processedBatches%10 == 0 { // Log every 10 batches
+			if total > 0 {
+				log.Printf("Processed %d/%d batches (%.1f%%)",
+					processedBatches, total, float64(processedBatches)/float64(total)*100)
+			} else {
+				log.Printf("Processed %d batches", processedBatches)
+			}
+		}
+	}
 
-	// Wait for all workers to complete
-	select {
-	case <-doneChan:
-		// All workers completed successfully
-	case err := <-errorChan:
-		return fmt.Errorf("worker error: %w", err)
-	case <-ctx.Done():
-		return ctx.Err()
+	// Every worker has stopped, so no batch is left half written
+	stream := <-streamChan
+	if err := ctx.Err(); err != nil {
+		log.Printf("MTG import cancelled; checkpoint saved at %d batches", nextUncommitted)
+		return err
+	}
+	if firstErr != nil {
+		return firstErr
+	}
+	if stream.err != nil {
+		return stream.err
 	}
 
 	processDuration := time.Since(processStart)
 	log.Printf("Batch processing completed in %v", processDuration)
 
+	// The run completed, so the next one starts from the beginning
+	if err := i.checkpoints.Clear(ctx, "mtg"); err != nil {
+		return err
+	}
+
 	// Update the last import timestamp
 	if err := i.updateLastImportTimestamp(ctx, time.Now()); err != nil {
 		return fmt.Errorf("failed to update last import timestamp: %w", err)
@@ -368,19 +460,87 @@ func (i *MTGImporter) ImportBulkData(ctx context.Context) error {
 	log.Printf("Import completed in %v", totalDuration)
 	log.Printf("Summary:")
 	log.Printf("  - Download time: %v", downloadStart.Sub(startTime))
-	log.Printf("  - Batch building time: %v", buildDuration)
 	log.Printf("  - Batch processing time: %v", processDuration)
 	log.Printf("  - Total time: %v", totalDuration)
-	log.Printf("  - Total cards processed: %d", processedCards)
+	log.Printf("  - Total cards processed: %d", stream.cards)
 	log.Printf("  - Total cards inserted: %d", totalInserted)
 	log.Printf("  - Total cards updated: %d", totalUpdated)
-	log.Printf("  - Total batches: %d", len(batches))
-	log.Printf("  - Average time per batch: %v", processDuration/time.Duration(len(batches)))
-	log.Printf("  - Average time per card: %v", totalDuration/time.Duration(processedCards))
+	log.Printf("  - Total batches: %d", stream.batches)
+	if stream.batches > 0 {
+		log.Printf("  - Average time per batch: %v", processDuration/time.Duration(stream.batches))
+		log.Printf("  - Average time per card: %v", totalDuration/time.Duration(stream.cards))
+	}
 
 	return nil
 }
 
+// mtgBatch is a batch of cards cut from the bulk data file, numbered from zero
+type mtgBatch struct {
+	index int
+	cards []MTGAPICard
+}
+
+// mtgBatchResult is the outcome of processing one batch
+type mtgBatchResult struct {
+	index, size, inserted, updated int
+	err                            error
+}
+
+// mtgStreamResult is the outcome of streaming the bulk data file
+type mtgStreamResult struct {
+	cards, batches int
+	err            error
+}
+
+// streamMTGBatches decodes the cards of a bulk data array and sends them to out in batches of
+// batchSize, skipping the first skip batches. Sends block while out is full, so memory holds
+// only the batches buffered in out and those being processed, never the whole file. It returns
+// the number of cards decoded and batches cut, and stops early when stop is closed or ctx is done.
+func streamMTGBatches(ctx context.Context, decoder *json.Decoder, batchSize, skip int, out chan<- mtgBatch, stop <-chan struct{}) (int, int, error) {
+	var cards, batches int
+	current := make([]MTGAPICard, 0, batchSize)
+
+	send := func() bool {
+		batch := mtgBatch{index: batches, cards: current}
+		batches++
+		current = make([]MTGAPICard, 0, batchSize)
+
+		if batch.index < skip {
+			return true
+		}
+
+		select {
+		case out <- batch:
+			return true
+		case <-stop:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for decoder.More() {
+		var card MTGAPICard
+		if err := decoder.Decode(&card); err != nil {
+			return cards, batches, fmt.Errorf("failed to decode card: %w", err)
+		}
+
+		current = append(current, card)
+		cards++
+
+		if len(current) >= batchSize && !send() {
+			return cards, batches, nil
+		}
+	}
+
+	// Send the final batch if it has any cards
+	if len(current) > 0 && !send() {
+		return cards, batches, nil
+	}
+
+	return cards, batches, nil
+}
+
 // CardSignature represents the fields that determine if a card needs updating
 type CardSignature struct {
 	Name       string            `json:"name"`
@@ -396,6 +556,7 @@ type CardSignature struct {
 	Colors     []string          `json:"colors"`
 	Keywords   []string          `json:"keywords"`
 	Legalities map[string]string `json:"legalities"`
+	PriceUSD   *float64          `json:"priceUsd"`
 }
 
 func (c *CardSignature) Hash() string {
@@ -406,13 +567,13 @@ func (c *CardSignature) Hash() string {
 
 func (i *MTGImporter) processBatch(ctx context.Context, batch []MTGAPICard, lastImport *time.Time, batchNumber int) (int, int, error) {
 	// First, check which cards exist and which need to be created
-	var cardsToCreate []*types.Card
-	var cardsToUpdate []*types.Card
+	var cardsToCreate []*models.Card
+	var cardsToUpdate []*models.Card
 	var mtgCardsToCreate []*MTGCard
 	var mtgCardsToUpdate []*MTGCard
 
 	// Build a map of set+number to card for quick lookup
-	cardMap := make(map[string]*types.Card)
+	cardMap := make(map[string]*models.Card)
 	missingMtgCards := make(map[string]bool) // Track cards with missing mtg_cards records
 	for _, card := range batch {
 		if card.Set == "" || card.CollectorNumber == "" {
@@ -426,7 +587,7 @@ func (i *MTGImporter) processBatch(ctx context.Context, batch []MTGAPICard, last
 	// Fetch all existing cards in a single query
 	if len(cardMap) > 0 {
 		query := `
-			SELECT c.id, c.name, c.game, c.set_code, c.set_name, c.number, c.rarity, c.image_url, c.created_at, c.updated_at,
+			SELECT c.id, c.name, c.game, c.set_code, c.set_name, c.number, c.rarity, c.image_url, c.created_at, c.updated_at, c.price_usd,
 				       m.id as mtg_id, m.mana_cost, m.cmc, m.type_line, m.oracle_text, m.power, m.toughness, m.loyalty,
 				       m.colors, m.color_identity, m.keywords, m.legalities, m.reserved, m.foil, m.nonfoil,
 				       m.promo, m.reprint, m.variation, m.set_type, m.released_at
@@ -450,7 +611,7 @@ func (i *MTGImporter) processBatch(ctx context.Context, batch []MTGAPICard, last
 		defer rows.Close()
 
 		for rows.Next() {
-			var card types.Card
+			var card models.Card
 			var mtgCard MTGCard
 			var legalitiesJSON []byte
 			var manaCost, typeLine, oracleText, power, toughness, loyalty sql.NullString
@@ -460,6 +621,7 @@ func (i *MTGImporter) processBatch(ctx context.Context, batch []MTGAPICard, last
 			var setType sql.NullString
 			var releasedAt sql.NullTime
 			var mtgID sql.NullString
+			var price sql.NullFloat64
 
 			err := rows.Scan(
 				&card.ID,
@@ -469,9 +631,10 @@ func (i *MTGImporter) processBatch(ctx context.Context, batch []MTGAPICard, last
 				&card.SetName,
 				&card.Number,
 				&card.Rarity,
-				&card.ImageURL,
+				&card.ImageUrl,
 				&card.CreatedAt,
 				&card.UpdatedAt,
+				&price,
 				&mtgID,
 				&manaCost,
 				&cmc,
@@ -496,6 +659,9 @@ func (i *MTGImporter) processBatch(ctx context.Context, batch []MTGAPICard, last
 			if err != nil {
 				return 0, 0, fmt.Errorf("failed to scan card: %w", err)
 			}
+			if price.Valid {
+				card.PriceUsd = &price.Float64
+			}
 
 			// If mtg_id is null, it means the mtg_cards record was deleted
 			if !mtgID.Valid {
@@ -567,14 +733,15 @@ func (i *MTGImporter) processBatch(ctx context.Context, batch []MTGAPICard, last
 		existingCard := cardMap[key]
 
 		// Create base card
-		baseCard := &types.Card{
+		baseCard := &models.Card{
 			Name:      card.Name,
 			Game:      "mtg",
 			SetCode:   card.Set,
 			SetName:   card.SetName,
 			Number:    card.CollectorNumber,
 			Rarity:    card.Rarity,
-			ImageURL:  card.ImageURIs.Large,
+			ImageUrl:  card.ImageURIs.Large,
+			PriceUsd:  card.priceUSD(),
 			UpdatedAt: time.Now(),
 		}
 
@@ -583,6 +750,8 @@ func (i *MTGImporter) processBatch(ctx context.Context, batch []MTGAPICard, last
 		if err != nil {
 			log.Printf("Warning: failed to parse release date for card %s: %v", card.Name, err)
 			releasedAt = time.Now()
+		} else {
+			baseCard.ReleasedAt = &releasedAt
 		}
 
 		mtgCard := &MTGCard{
@@ -633,7 +802,7 @@ func (i *MTGImporter) processBatch(ctx context.Context, batch []MTGAPICard, last
 				Name:       baseCard.Name,
 				SetName:    baseCard.SetName,
 				Rarity:     baseCard.Rarity,
-				ImageURL:   baseCard.ImageURL,
+				ImageURL:   baseCard.ImageUrl,
 				ManaCost:   card.ManaCost,
 				TypeLine:   card.TypeLine,
 				OracleText: card.OracleText,
@@ -643,6 +812,7 @@ func (i *MTGImporter) processBatch(ctx context.Context, batch []MTGAPICard, last
 				Colors:     card.Colors,
 				Keywords:   card.Keywords,
 				Legalities: card.Legalities,
+				PriceUSD:   baseCard.PriceUsd,
 			}
 
 			// Create signature for the existing card
@@ -650,7 +820,7 @@ func (i *MTGImporter) processBatch(ctx context.Context, batch []MTGAPICard, last
 				Name:       existingCard.Name,
 				SetName:    existingCard.SetName,
 				Rarity:     existingCard.Rarity,
-				ImageURL:   existingCard.ImageURL,
+				ImageURL:   existingCard.ImageUrl,
 				ManaCost:   mtgCard.ManaCost,
 				TypeLine:   mtgCard.TypeLine,
 				OracleText: mtgCard.OracleText,
@@ -660,6 +830,7 @@ func (i *MTGImporter) processBatch(ctx context.Context, batch []MTGAPICard, last
 				Colors:     mtgCard.Colors,
 				Keywords:   mtgCard.Keywords,
 				Legalities: mtgCard.Legalities,
+				PriceUSD:   existingCard.PriceUsd,
 			}
 
 			// Compare hashes to determine if update is needed
@@ -680,27 +851,8 @@ func (i *MTGImporter) processBatch(ctx context.Context, batch []MTGAPICard, last
 	err := database.WithTransaction(ctx, i.cardStore.db, func(tx *database.Transaction) error {
 		if len(cardsToCreate) > 0 {
 			log.Printf("Batch %d: Creating %d new base cards", batchNumber, len(cardsToCreate))
-			// Create base cards
-			query := `
-				INSERT INTO cards (id, name, game, set_code, set_name, number, rarity, image_url, created_at, updated_at)
-				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-			`
-			for _, card := range cardsToCreate {
-				_, err := tx.Exec(query,
-					card.ID,
-					card.Name,
-					card.Game,
-					card.SetCode,
-					card.SetName,
-					card.Number,
-					card.Rarity,
-					card.ImageURL,
-					card.CreatedAt,
-					card.UpdatedAt,
-				)
-				if err != nil {
-					return fmt.Errorf("failed to create card %s (%s): %w", card.Name, card.Number, err)
-				}
+			if err := i.cardStore.CreateBatch(ctx, tx.Tx(), cardsToCreate); err != nil {
+				return err
 			}
 		}
 
@@ -755,27 +907,8 @@ func (i *MTGImporter) processBatch(ctx context.Context, batch []MTGAPICard, last
 
 		if len(cardsToUpdate) > 0 {
 			log.Printf("Batch %d: Updating %d existing cards", batchNumber, len(cardsToUpdate))
-			// Update base cards
-			updateQuery := `
-				UPDATE cards
-				SET name = $1, game = $2, set_code = $3, set_name = $4, number = $5, rarity = $6, image_url = $7, updated_at = $8
-				WHERE id = $9
-			`
-			for _, card := range cardsToUpdate {
-				_, err := tx.Exec(updateQuery,
-					card.Name,
-					card.Game,
-					card.SetCode,
-					card.SetName,
-					card.Number,
-					card.Rarity,
-					card.ImageURL,
-					card.UpdatedAt,
-					card.ID,
-				)
-				if err != nil {
-					return fmt.Errorf("failed to update card %s (%s): %w", card.Name, card.Number, err)
-				}
+			if err := i.cardStore.UpdateBatch(ctx, tx.Tx(), cardsToUpdate); err != nil {
+				return err
 			}
 		}
 
@@ -902,6 +1035,21 @@ type MTGAPICard struct {
 	SetType       string            `json:"set_type"`
 	ReleasedAt    string            `json:"released_at"`
 	UpdatedAt     string            `json:"updated_at"`
+	Prices        struct {
+		USD string `json:"usd"`
+	} `json:"prices"`
+}
+
+// priceUSD returns the card's market price in US dollars, or nil when Scryfall has none
+func (c *MTGAPICard) priceUSD() *float64 {
+	if c.Prices.USD == "" {
+		return nil
+	}
+	price, err := strconv.ParseFloat(c.Prices.USD, 64)
+	if err != nil {
+		return nil
+	}
+	return &price
 }
 
 // GetGame returns the game type for this importer
@@ -0,0 +1,176 @@
+package cards
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestOpenBulkDataKeysFilesByContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mtg_bulk_data.json")
+	importer := NewMTGImporterWithSource(NewCardStore(nil), nil, NewFileSource(path))
+
+	open := func(content string) string {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		reader, source, err := importer.openBulkData(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer reader.Close()
+
+		// Hashing must leave the file ready to be imported from the start
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != content {
+			t.Fatalf("read %q after hashing, want %q", data, content)
+		}
+		return source
+	}
+
+	first := open(`[{"name":"Opt"}]`)
+	again := open(`[{"name":"Opt"}]`)
+	replaced := open(`[{"name":"Shock"}]`)
+
+	if first != again {
+		t.Errorf("same content gave sources %q and %q", first, again)
+	}
+	if first == replaced {
+		t.Errorf("different content at the same path shared source %q, so a checkpoint would skip its batches", first)
+	}
+}
+
+// writeBulkDataFixture writes a Scryfall-shaped default cards file of n cards
+func writeBulkDataFixture(tb testing.TB, n int) string {
+	tb.Helper()
+	path := filepath.Join(tb.TempDir(), fmt.Sprintf("default-cards-%d.json", n))
+	file, err := os.Create(path)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer file.Close()
+
+	legalities := make(map[string]string)
+	for _, format := range []string{"standard", "pioneer", "modern", "legacy", "vintage", "commander", "pauper", "brawl", "historic", "alchemy", "explorer", "timeless", "oathbreaker", "penny", "duel", "oldschool", "premodern", "predh", "gladiator", "future"} {
+		legalities[format] = "legal"
+	}
+	oracle := strings.Repeat("When this creature enters, draw a card, then discard a card. ", 5)
+
+	w := bufio.NewWriter(file)
+	encoder := json.NewEncoder(w)
+	w.WriteString("[\n")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			w.WriteString(",\n")
+		}
+		card := MTGAPICard{
+			ID:              fmt.Sprintf("00000000-0000-0000-0000-%012d", i),
+			Name:            fmt.Sprintf("Fixture Card %d", i),
+			Set:             fmt.Sprintf("s%03d", i/300),
+			SetName:         fmt.Sprintf("Fixture Set %d", i/300),
+			CollectorNumber: fmt.Sprint(i % 300),
+			Rarity:          "common",
+			ManaCost:        "{1}{U}",
+			CMC:             2,
+			TypeLine:        "Creature — Merfolk Wizard",
+			OracleText:      oracle,
+			Power:           "1",
+			Toughness:       "2",
+			Colors:          []string{"U"},
+			ColorIdentity:   []string{"U"},
+			Keywords:        []string{"Flying"},
+			Legalities:      legalities,
+			Nonfoil:         true,
+			SetType:         "expansion",
+			ReleasedAt:      "2024-01-01",
+			UpdatedAt:       "2024-01-01T00:00:00Z",
+		}
+		card.ImageURIs.Large = "https://cards.scryfall.io/large/front/0/0/fixture.jpg"
+		card.Prices.USD = "0.25"
+		if err := encoder.Encode(card); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	w.WriteString("]\n")
+	if err := w.Flush(); err != nil {
+		tb.Fatal(err)
+	}
+	return path
+}
+
+// BenchmarkStreamMTGBatches streams bulk data files of increasing size through the batch
+// pipeline with a stub batch processor. The peak heap stays flat as the file grows, since
+// only the batches in flight are held in memory.
+func BenchmarkStreamMTGBatches(b *testing.B) {
+	const batchSize, workers = 100, 8
+
+	for _, n := range []int{10_000, 40_000, 160_000} {
+		path := writeBulkDataFixture(b, n)
+		info, err := os.Stat(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(fmt.Sprintf("cards=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(info.Size())
+
+			var peak uint64
+			for i := 0; i < b.N; i++ {
+				file, err := os.Open(path)
+				if err != nil {
+					b.Fatal(err)
+				}
+				decoder := json.NewDecoder(file)
+				if _, err := decoder.Token(); err != nil {
+					b.Fatal(err)
+				}
+
+				runtime.GC()
+				batches := make(chan mtgBatch, workers)
+				done := make(chan uint64)
+				go func() {
+					// Stand in for the workers, sampling the heap as batches arrive
+					var stats runtime.MemStats
+					var max uint64
+					for batch := range batches {
+						if batch.index%50 == 0 {
+							runtime.ReadMemStats(&stats)
+							if stats.HeapInuse > max {
+								max = stats.HeapInuse
+							}
+						}
+					}
+					done <- max
+				}()
+
+				cards, _, err := streamMTGBatches(context.Background(), decoder, batchSize, 0, batches, make(chan struct{}))
+				close(batches)
+				max := <-done
+				file.Close()
+				if err != nil {
+					b.Fatal(err)
+				}
+				if cards != n {
+					b.Fatalf("streamed %d cards, want %d", cards, n)
+				}
+				if max > peak {
+					peak = max
+				}
+			}
+			b.ReportMetric(float64(peak)/(1<<20), "peak-heap-MiB")
+			b.ReportMetric(float64(info.Size())/(1<<20), "file-MiB")
+		})
+	}
+}
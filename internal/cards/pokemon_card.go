@@ -233,3 +233,135 @@ func (s *PokemonCardStore) Update(ctx context.Context, card *PokemonCard) error
 
 	return nil
 }
+
+// FindExistingCardIDs returns which of the given cards already have Pokémon details
+func (s *PokemonCardStore) FindExistingCardIDs(ctx context.Context, tx *sql.Tx, cardIDs []string) (map[string]bool, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT card_id FROM pokemon_cards WHERE card_id = ANY($1::uuid[])`, pq.Array(cardIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find existing pokemon cards: %w", err)
+	}
+	defer rows.Close()
+
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var cardID string
+		if err := rows.Scan(&cardID); err != nil {
+			return nil, fmt.Errorf("failed to scan pokemon card: %w", err)
+		}
+		existing[cardID] = true
+	}
+	return existing, rows.Err()
+}
+
+// CreateBatch inserts the Pokémon details of multiple cards
+func (s *PokemonCardStore) CreateBatch(ctx context.Context, tx *sql.Tx, cards []*PokemonCard) error {
+	query := `
+		INSERT INTO pokemon_cards (
+			card_id, hp, evolves_from, evolves_to, types, subtypes,
+			supertype, rules, abilities, attacks, weaknesses,
+			resistances, retreat_cost
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, card := range cards {
+		abilitiesJSON, attacksJSON, weaknessesJSON, resistancesJSON, err := marshalPokemonDetails(card)
+		if err != nil {
+			return err
+		}
+
+		_, err = stmt.ExecContext(ctx,
+			card.CardID,
+			card.HP,
+			card.EvolvesFrom,
+			pq.Array(card.EvolvesTo),
+			pq.Array(card.Types),
+			pq.Array(card.Subtypes),
+			card.Supertype,
+			pq.Array(card.Rules),
+			abilitiesJSON,
+			attacksJSON,
+			weaknessesJSON,
+			resistancesJSON,
+			pq.Array(card.RetreatCost),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create pokemon card: %w", err)
+		}
+	}
+	return nil
+}
+
+// UpdateBatch updates the Pokémon details of multiple cards, matching them by card ID
+func (s *PokemonCardStore) UpdateBatch(ctx context.Context, tx *sql.Tx, cards []*PokemonCard) error {
+	query := `
+		UPDATE pokemon_cards
+		SET hp = $1,
+			evolves_from = $2,
+			evolves_to = $3,
+			types = $4,
+			subtypes = $5,
+			supertype = $6,
+			rules = $7,
+			abilities = $8,
+			attacks = $9,
+			weaknesses = $10,
+			resistances = $11,
+			retreat_cost = $12,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE card_id = $13
+	`
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, card := range cards {
+		abilitiesJSON, attacksJSON, weaknessesJSON, resistancesJSON, err := marshalPokemonDetails(card)
+		if err != nil {
+			return err
+		}
+
+		_, err = stmt.ExecContext(ctx,
+			card.HP,
+			card.EvolvesFrom,
+			pq.Array(card.EvolvesTo),
+			pq.Array(card.Types),
+			pq.Array(card.Subtypes),
+			card.Supertype,
+			pq.Array(card.Rules),
+			abilitiesJSON,
+			attacksJSON,
+			weaknessesJSON,
+			resistancesJSON,
+			pq.Array(card.RetreatCost),
+			card.CardID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update pokemon card: %w", err)
+		}
+	}
+	return nil
+}
+
+// marshalPokemonDetails encodes the JSON columns of a Pokémon card
+func marshalPokemonDetails(card *PokemonCard) (abilities, attacks, weaknesses, resistances []byte, err error) {
+	if abilities, err = json.Marshal(card.Abilities); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to marshal abilities: %w", err)
+	}
+	if attacks, err = json.Marshal(card.Attacks); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to marshal attacks: %w", err)
+	}
+	if weaknesses, err = json.Marshal(card.Weaknesses); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to marshal weaknesses: %w", err)
+	}
+	if resistances, err = json.Marshal(card.Resistances); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to marshal resistances: %w", err)
+	}
+	return abilities, attacks, weaknesses, resistances, nil
+}
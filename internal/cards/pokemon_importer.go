@@ -1,38 +1,104 @@
 package cards
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/shiftregister-vg/card-craft/internal/types"
+	"github.com/shiftregister-vg/card-craft/internal/database"
+	"github.com/shiftregister-vg/card-craft/internal/models"
+)
+
+func init() {
+	Register(ImporterRegistration{
+		Game:         "pokemon",
+		DisplayName:  "Pokémon",
+		DetailTable:  "pokemon_cards",
+		Capabilities: []Capability{CapabilityFullImport, CapabilitySingleSet},
+		New: func(store *CardStore, db *sql.DB) CardImporter {
+			return NewPokemonImporter(store, NewPokemonCardStore(db))
+		},
+		NewWithSource: func(store *CardStore, db *sql.DB, source DataSource) CardImporter {
+			return NewPokemonImporterWithSource(store, NewPokemonCardStore(db), source)
+		},
+	})
+}
+
+// PokemonAPIURL is the base URL of the Pokémon TCG API
+const PokemonAPIURL = "https://api.pokemontcg.io/v2"
+
+const (
+	// pokemonSetWorkers is how many sets are imported at the same time
+	pokemonSetWorkers = 4
+	// pokemonRequestsPerSecond and pokemonRequestBurst bound the requests all workers make together
+	pokemonRequestsPerSecond = 4
+	pokemonRequestBurst      = 4
+	pokemonMaxAttempts       = 5
+	pokemonPageSize          = 250
+	// pokemonSetTimeLayout is the layout of the API's set timestamps, such as "2024/03/22 10:35:00"
+	pokemonSetTimeLayout = "2006/01/02 15:04:05"
+	// pokemonReleaseDateLayout is the layout of the API's set release dates, such as "2024/03/22"
+	pokemonReleaseDateLayout = "2006/01/02"
 )
 
 // PokemonImporter handles importing Pokémon card data
 type PokemonImporter struct {
 	cardStore    *CardStore
 	pokemonStore *PokemonCardStore
+	setSyncs     *SetSyncStore
 	apiKey       string
+	// source holds a local dump of Pokémon TCG API card objects. When nil,
+	// cards are fetched from the Pokémon TCG API.
+	source DataSource
+	// client and limiter are shared by every request the importer makes, so concurrent
+	// set imports stay within the API's rate limit together
+	client  *http.Client
+	limiter *RateLimiter
 }
 
-// NewPokemonImporter creates a new Pokémon importer
+// NewPokemonImporter creates a new Pokémon importer that reads from the Pokémon TCG API using
+// the key in POKEMON_TCG_API_KEY. Without a key the importer is not configured and refuses to run.
 func NewPokemonImporter(cardStore *CardStore, pokemonStore *PokemonCardStore) *PokemonImporter {
-	apiKey := os.Getenv("POKEMON_TCG_API_KEY")
-	if apiKey == "" {
-		panic("POKEMON_TCG_API_KEY environment variable is not set")
+	return &PokemonImporter{
+		cardStore:    cardStore,
+		pokemonStore: pokemonStore,
+		setSyncs:     NewSetSyncStore(cardStore.db),
+		apiKey:       os.Getenv("POKEMON_TCG_API_KEY"),
+		client: &http.Client{
+			Timeout: time.Minute,
+		},
+		limiter: NewRateLimiter(pokemonRequestsPerSecond, pokemonRequestBurst),
 	}
+}
 
+// NewPokemonImporterWithSource creates a new Pokémon importer that reads a dump of Pokémon TCG
+// API card objects from the given source. The dump is either a JSON array of cards or an API
+// response with the cards under "data". No API key is needed.
+func NewPokemonImporterWithSource(cardStore *CardStore, pokemonStore *PokemonCardStore, source DataSource) *PokemonImporter {
 	return &PokemonImporter{
 		cardStore:    cardStore,
 		pokemonStore: pokemonStore,
-		apiKey:       apiKey,
+		setSyncs:     NewSetSyncStore(cardStore.db),
+		source:       source,
+	}
+}
+
+// CheckConfig implements the ConfigChecker interface. Reading the API needs a key; reading a
+// local source does not.
+func (i *PokemonImporter) CheckConfig() error {
+	if i.source == nil && i.apiKey == "" {
+		return fmt.Errorf("%w: POKEMON_TCG_API_KEY is not set", ErrNotConfigured)
 	}
+	return nil
 }
 
 // Import implements the Importer interface
@@ -42,193 +108,342 @@ func (i *PokemonImporter) Import(ctx context.Context, store *CardStore) error {
 
 // fetchSets fetches available sets from the Pokemon TCG API
 func (i *PokemonImporter) fetchSets(ctx context.Context) ([]PokemonSet, error) {
-	url := "https://api.pokemontcg.io/v2/sets?orderBy=-releaseDate"
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var response struct {
+		Data []PokemonSet `json:"data"`
+	}
+	if err := i.getJSON(ctx, PokemonAPIURL+"/sets?orderBy=-releaseDate", &response); err != nil {
+		return nil, err
 	}
 
-	req.Header.Set("X-Api-Key", i.apiKey)
+	return response.Data, nil
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch sets: %w", err)
-	}
-	defer resp.Body.Close()
+// getJSON fetches a URL from the Pokémon TCG API and decodes the response into out. Requests
+// wait on the importer's rate limiter; a 429 pauses the limiter for as long as Retry-After asks,
+// and other transient failures are retried with exponential backoff.
+func (i *PokemonImporter) getJSON(ctx context.Context, url string, out interface{}) error {
+	backoff := 2 * time.Second
+	var lastErr error
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to fetch sets: status %d, body: %s", resp.StatusCode, string(body))
-	}
+	for attempt := 1; attempt <= pokemonMaxAttempts; attempt++ {
+		if err := i.limiter.Wait(ctx); err != nil {
+			return err
+		}
 
-	var response struct {
-		Data []PokemonSet `json:"data"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("X-Api-Key", i.apiKey)
+
+		wait := backoff
+		resp, err := i.client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			lastErr = fmt.Errorf("request failed: %w", err)
+		} else if resp.StatusCode == http.StatusOK {
+			err := json.NewDecoder(resp.Body).Decode(out)
+			resp.Body.Close()
+			if err == nil {
+				return nil
+			}
+			lastErr = fmt.Errorf("failed to decode response: %w", err)
+		} else {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			lastErr = fmt.Errorf("status %d, body: %s", resp.StatusCode, string(body))
+
+			switch {
+			case resp.StatusCode == http.StatusTooManyRequests:
+				// Hold back every worker, not just this one
+				i.limiter.PauseUntil(time.Now().Add(retryAfter(resp, backoff)))
+				wait = 0
+			case resp.StatusCode >= http.StatusInternalServerError:
+				wait = retryAfter(resp, backoff)
+			default:
+				return fmt.Errorf("failed to fetch %s: %w", url, lastErr)
+			}
+		}
+
+		if attempt == pokemonMaxAttempts {
+			break
+		}
+
+		log.Printf("Request to %s failed (attempt %d/%d): %v", url, attempt, pokemonMaxAttempts, lastErr)
+		if wait > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+		backoff *= 2
 	}
 
-	return response.Data, nil
+	return fmt.Errorf("failed to fetch %s after %d attempts: %w", url, pokemonMaxAttempts, lastErr)
 }
 
 // ImportSet imports all cards from a specific set
 func (i *PokemonImporter) ImportSet(ctx context.Context, setID string) error {
-	baseURL := "https://api.pokemontcg.io/v2/cards"
-	page := 1
-	totalCards := 0
-	var cardsCreated, cardsUpdated int
+	if i.source != nil {
+		return i.importFromSource(ctx, setID)
+	}
+	if err := i.CheckConfig(); err != nil {
+		return err
+	}
+
+	existing, err := i.existingCards(ctx, setID)
+	if err != nil {
+		return err
+	}
 
-	for {
-		url := fmt.Sprintf("%s?q=set.id:%s&page=%d&pageSize=250", baseURL, setID, page)
+	var totalCards, cardsCreated, cardsUpdated int
+	for page := 1; ; page++ {
 		log.Printf("Fetching page %d for set %s", page, setID)
 
 		var response PokemonResponse
+		url := fmt.Sprintf("%s/cards?q=set.id:%s&page=%d&pageSize=%d", PokemonAPIURL, setID, page, pokemonPageSize)
+		if err := i.getJSON(ctx, url, &response); err != nil {
+			return fmt.Errorf("failed to fetch page %d: %w", page, err)
+		}
 
-		// Retry logic with exponential backoff
-		maxRetries := 3
-		backoff := 2 * time.Second
-		for retry := 0; retry < maxRetries; retry++ {
-			if retry > 0 {
-				log.Printf("Retry attempt %d for page %d after %v", retry, page, backoff)
-				time.Sleep(backoff)
-				backoff *= 2 // Exponential backoff
-			}
+		if len(response.Data) == 0 {
+			break
+		}
 
-			req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-			if err != nil {
-				return fmt.Errorf("failed to create request: %w", err)
-			}
+		log.Printf("Processing %d cards from page %d of set %s", len(response.Data), page, setID)
 
-			req.Header.Set("X-Api-Key", i.apiKey)
+		created, updated, err := i.saveCards(ctx, response.Data, existing)
+		if err != nil {
+			return err
+		}
+		cardsCreated += created
+		cardsUpdated += updated
+		totalCards += len(response.Data)
 
-			client := &http.Client{}
-			resp, err := client.Do(req)
-			if err != nil {
-				log.Printf("Request failed (attempt %d): %v", retry+1, err)
-				continue
-			}
+		if response.TotalCount > 0 && totalCards >= response.TotalCount {
+			break
+		}
+	}
 
-			if resp.StatusCode != http.StatusOK {
-				body, _ := io.ReadAll(resp.Body)
-				resp.Body.Close()
-				log.Printf("Request failed with status %d (attempt %d): %s", resp.StatusCode, retry+1, string(body))
-				continue
-			}
+	log.Printf("Set %s import completed: %d total cards processed (%d created, %d updated)", setID, totalCards, cardsCreated, cardsUpdated)
+	return nil
+}
 
-			if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-				resp.Body.Close()
-				log.Printf("Failed to decode response (attempt %d): %v", retry+1, err)
-				continue
-			}
-			resp.Body.Close()
+// importFromSource imports the cards of the importer's dump, limited to one set unless setID is empty
+func (i *PokemonImporter) importFromSource(ctx context.Context, setID string) error {
+	startTime := time.Now()
+	log.Printf("Starting Pokemon card import from %s", i.source.Name())
 
-			// If we get here, the request was successful
-			break
+	apiCards, err := i.fetchDump(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Group the cards by set, keeping the sets in the order they appear in the dump
+	var setIDs []string
+	bySet := make(map[string][]PokemonAPICard)
+	for _, apiCard := range apiCards {
+		if setID != "" && apiCard.Set.ID != setID {
+			continue
+		}
+		if _, ok := bySet[apiCard.Set.ID]; !ok {
+			setIDs = append(setIDs, apiCard.Set.ID)
 		}
+		bySet[apiCard.Set.ID] = append(bySet[apiCard.Set.ID], apiCard)
+	}
 
-		if len(response.Data) == 0 {
-			break
+	if setID != "" && len(setIDs) == 0 {
+		return fmt.Errorf("pokemon set %s not found in %s", setID, i.source.Name())
+	}
+
+	log.Printf("Found %d sets to import", len(setIDs))
+
+	progress := ProgressFromContext(ctx)
+	progress.SetBatches(0, len(setIDs))
+	for setIndex, id := range setIDs {
+		if err := ctx.Err(); err != nil {
+			return err
 		}
 
-		log.Printf("Processing %d cards from page %d", len(response.Data), page)
+		existing, err := i.existingCards(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to import set %s: %w", id, err)
+		}
 
-		for _, apiCard := range response.Data {
-			// Check if card already exists
-			existingCard, err := i.cardStore.FindByGameAndNumber("pokemon", apiCard.Set.ID, apiCard.Number)
-			if err != nil {
-				return fmt.Errorf("failed to check for existing card: %w", err)
-			}
+		cardsCreated, cardsUpdated, err := i.saveCards(ctx, bySet[id], existing)
+		if err != nil {
+			return fmt.Errorf("failed to import set %s: %w", id, err)
+		}
 
-			var cardID uuid.UUID
-			if existingCard != nil {
-				cardID = existingCard.ID
-			} else {
-				cardID = uuid.New()
-			}
+		log.Printf("Set %s import completed: %d total cards processed (%d created, %d updated)", id, len(bySet[id]), cardsCreated, cardsUpdated)
+		progress.SetBatches(setIndex+1, len(setIDs))
+	}
 
-			card := &types.Card{
-				ID:        cardID,
-				Name:      apiCard.Name,
-				Game:      "pokemon",
-				SetCode:   apiCard.Set.ID,
-				SetName:   apiCard.Set.Name,
-				Number:    apiCard.Number,
-				Rarity:    apiCard.Rarity,
-				ImageURL:  apiCard.Images.Large,
-				CreatedAt: time.Now(),
-				UpdatedAt: time.Now(),
-			}
+	log.Printf("Pokemon import completed in %s", time.Since(startTime))
+	return nil
+}
 
-			if existingCard != nil {
-				card.CreatedAt = existingCard.CreatedAt
-				if err := i.cardStore.Update(card); err != nil {
-					return fmt.Errorf("failed to update card %s (%s): %w", card.Name, card.Number, err)
-				}
-				cardsUpdated++
-			} else {
-				if err := i.cardStore.Create(card); err != nil {
-					return fmt.Errorf("failed to create card %s (%s): %w", card.Name, card.Number, err)
-				}
-				cardsCreated++
-			}
+// fetchDump reads and decodes the importer's dump of Pokémon TCG API card objects
+func (i *PokemonImporter) fetchDump(ctx context.Context) ([]PokemonAPICard, error) {
+	reader, err := i.source.Open(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pokemon card data: %w", err)
+	}
+	defer reader.Close()
 
-			// Convert HP to int if it's a number
-			var hp int
-			if apiCard.HP != "" {
-				_, err := fmt.Sscanf(apiCard.HP, "%d", &hp)
-				if err != nil {
-					hp = 0
-				}
-			}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pokemon card data: %w", err)
+	}
 
-			pokemonCard := &PokemonCard{
-				CardID:      cardID.String(),
-				HP:          hp,
-				EvolvesFrom: apiCard.EvolvesFrom,
-				EvolvesTo:   apiCard.EvolvesTo,
-				Types:       apiCard.Types,
-				Subtypes:    apiCard.Subtypes,
-				Supertype:   apiCard.Supertype,
-				Rules:       apiCard.Rules,
-				Abilities:   apiCard.Abilities,
-				Attacks:     apiCard.Attacks,
-				Weaknesses:  apiCard.Weaknesses,
-				Resistances: apiCard.Resistances,
-				RetreatCost: apiCard.RetreatCost,
-				CreatedAt:   time.Now(),
-				UpdatedAt:   time.Now(),
-			}
+	// Accept both a bare array of cards and an API response
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var apiCards []PokemonAPICard
+		if err := json.Unmarshal(trimmed, &apiCards); err != nil {
+			return nil, fmt.Errorf("failed to decode pokemon card data: %w", err)
+		}
+		return apiCards, nil
+	}
+
+	var response PokemonResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode pokemon card data: %w", err)
+	}
+	return response.Data, nil
+}
+
+// existingCards loads the cards already stored for a set, keyed by card number
+func (i *PokemonImporter) existingCards(ctx context.Context, setID string) (map[string]*models.Card, error) {
+	cards, err := i.cardStore.FindBySet(ctx, "pokemon", setID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load existing cards of set %s: %w", setID, err)
+	}
+
+	existing := make(map[string]*models.Card, len(cards))
+	for _, card := range cards {
+		existing[card.Number] = card
+	}
+	return existing, nil
+}
+
+// saveCards creates or updates a page of cards of one set and their Pokémon details in a single
+// transaction. existing holds the set's stored cards by number and is updated with the new ones.
+func (i *PokemonImporter) saveCards(ctx context.Context, apiCards []PokemonAPICard, existing map[string]*models.Card) (created, updated int, err error) {
+	now := time.Now()
+	var cardsToCreate, cardsToUpdate []*models.Card
+	var pokemonCards []*PokemonCard
+	seen := make(map[string]bool, len(apiCards))
+
+	for _, apiCard := range apiCards {
+		if seen[apiCard.Number] {
+			log.Printf("Skipping duplicate card %s (%s) in set %s", apiCard.Name, apiCard.Number, apiCard.Set.ID)
+			continue
+		}
+		seen[apiCard.Number] = true
+
+		card := &models.Card{
+			Name:      apiCard.Name,
+			Game:      "pokemon",
+			SetCode:   apiCard.Set.ID,
+			SetName:   apiCard.Set.Name,
+			Number:    apiCard.Number,
+			Rarity:    apiCard.Rarity,
+			ImageUrl:  apiCard.Images.Large,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if releasedAt, err := time.Parse(pokemonReleaseDateLayout, apiCard.Set.ReleaseDate); err == nil {
+			card.ReleasedAt = &releasedAt
+		}
+
+		if existingCard, ok := existing[apiCard.Number]; ok {
+			card.ID = existingCard.ID
+			card.CreatedAt = existingCard.CreatedAt
+			cardsToUpdate = append(cardsToUpdate, card)
+		} else {
+			card.ID = uuid.New()
+			cardsToCreate = append(cardsToCreate, card)
+		}
+		existing[apiCard.Number] = card
 
-			existingPokemonCard, err := i.pokemonStore.FindByCardID(ctx, cardID.String())
-			if err != nil {
-				return fmt.Errorf("failed to check for existing pokemon card: %w", err)
+		// Convert HP to int if it's a number
+		var hp int
+		if apiCard.HP != "" {
+			if _, err := fmt.Sscanf(apiCard.HP, "%d", &hp); err != nil {
+				hp = 0
 			}
+		}
 
-			if existingPokemonCard != nil {
-				pokemonCard.ID = existingPokemonCard.ID
-				pokemonCard.CreatedAt = existingPokemonCard.CreatedAt
-				if err := i.pokemonStore.Update(ctx, pokemonCard); err != nil {
-					return fmt.Errorf("failed to update pokemon card for %s (%s): %w", card.Name, card.Number, err)
-				}
+		pokemonCards = append(pokemonCards, &PokemonCard{
+			CardID:      card.ID.String(),
+			HP:          hp,
+			EvolvesFrom: apiCard.EvolvesFrom,
+			EvolvesTo:   apiCard.EvolvesTo,
+			Types:       apiCard.Types,
+			Subtypes:    apiCard.Subtypes,
+			Supertype:   apiCard.Supertype,
+			Rules:       apiCard.Rules,
+			Abilities:   apiCard.Abilities,
+			Attacks:     apiCard.Attacks,
+			Weaknesses:  apiCard.Weaknesses,
+			Resistances: apiCard.Resistances,
+			RetreatCost: apiCard.RetreatCost,
+		})
+	}
+
+	err = database.WithTransaction(ctx, i.cardStore.db, func(tx *database.Transaction) error {
+		if err := i.cardStore.CreateBatch(ctx, tx.Tx(), cardsToCreate); err != nil {
+			return err
+		}
+		if err := i.cardStore.UpdateBatch(ctx, tx.Tx(), cardsToUpdate); err != nil {
+			return err
+		}
+
+		// Cards imported before their details were stored may still lack them
+		cardIDs := make([]string, len(pokemonCards))
+		for index, pokemonCard := range pokemonCards {
+			cardIDs[index] = pokemonCard.CardID
+		}
+		withDetails, err := i.pokemonStore.FindExistingCardIDs(ctx, tx.Tx(), cardIDs)
+		if err != nil {
+			return err
+		}
+
+		var detailsToCreate, detailsToUpdate []*PokemonCard
+		for _, pokemonCard := range pokemonCards {
+			if withDetails[pokemonCard.CardID] {
+				detailsToUpdate = append(detailsToUpdate, pokemonCard)
 			} else {
-				if err := i.pokemonStore.Create(ctx, pokemonCard); err != nil {
-					return fmt.Errorf("failed to create pokemon card for %s (%s): %w", card.Name, card.Number, err)
-				}
+				detailsToCreate = append(detailsToCreate, pokemonCard)
 			}
-
-			totalCards++
 		}
 
-		page++
+		if err := i.pokemonStore.CreateBatch(ctx, tx.Tx(), detailsToCreate); err != nil {
+			return err
+		}
+		return i.pokemonStore.UpdateBatch(ctx, tx.Tx(), detailsToUpdate)
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to save pokemon cards: %w", err)
 	}
 
-	log.Printf("Set %s import completed: %d total cards processed (%d created, %d updated)", setID, totalCards, cardsCreated, cardsUpdated)
-	return nil
+	ProgressFromContext(ctx).AddCards(len(cardsToCreate), len(cardsToUpdate), 0)
+	return len(cardsToCreate), len(cardsToUpdate), nil
 }
 
-// ImportLatestSets imports cards from the latest Pokémon sets
+// ImportLatestSets imports every Pokémon set that changed since it was last imported.
+// Sets are imported concurrently, sharing the importer's rate limiter.
 func (i *PokemonImporter) ImportLatestSets(ctx context.Context) error {
+	if i.source != nil {
+		return i.importFromSource(ctx, "")
+	}
+	if err := i.CheckConfig(); err != nil {
+		return err
+	}
+
 	startTime := time.Now()
 	log.Printf("Starting Pokemon card import process")
 
@@ -238,14 +453,74 @@ func (i *PokemonImporter) ImportLatestSets(ctx context.Context) error {
 		return fmt.Errorf("failed to fetch sets: %w", err)
 	}
 
-	log.Printf("Found %d sets to import", len(sets))
+	synced, err := i.setSyncs.List(ctx, "pokemon")
+	if err != nil {
+		return err
+	}
 
-	// Import each set
-	for setIndex, set := range sets {
-		log.Printf("Importing set %d/%d: %s (%s)", setIndex+1, len(sets), set.Name, set.ID)
-		if err := i.ImportSet(ctx, set.ID); err != nil {
-			return fmt.Errorf("failed to import set %s: %w", set.ID, err)
+	var pending []PokemonSet
+	for _, set := range sets {
+		updatedAt, ok := set.ParseUpdatedAt()
+		if last, seen := synced[set.ID]; ok && seen && !updatedAt.After(last) {
+			continue
 		}
+		pending = append(pending, set)
+	}
+
+	log.Printf("Found %d sets, %d changed since the last import", len(sets), len(pending))
+
+	progress := ProgressFromContext(ctx)
+	progress.SetBatches(0, len(pending))
+
+	// The first failing set cancels the others
+	importCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	setChan := make(chan PokemonSet)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	processed := 0
+
+	for w := 0; w < pokemonSetWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for set := range setChan {
+				log.Printf("Importing set %s (%s)", set.Name, set.ID)
+				err := i.importChangedSet(importCtx, set)
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = fmt.Errorf("failed to import set %s: %w", set.ID, err)
+						cancel()
+					}
+				} else {
+					processed++
+					progress.SetBatches(processed, len(pending))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, set := range pending {
+		select {
+		case setChan <- set:
+		case <-importCtx.Done():
+			break feed
+		}
+	}
+	close(setChan)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
 	duration := time.Since(startTime)
@@ -253,8 +528,25 @@ func (i *PokemonImporter) ImportLatestSets(ctx context.Context) error {
 	return nil
 }
 
+// importChangedSet imports a set and remembers the revision it was imported at
+func (i *PokemonImporter) importChangedSet(ctx context.Context, set PokemonSet) error {
+	if err := i.ImportSet(ctx, set.ID); err != nil {
+		return err
+	}
+
+	updatedAt, ok := set.ParseUpdatedAt()
+	if !ok {
+		return nil
+	}
+	return i.setSyncs.Save(ctx, "pokemon", set.ID, updatedAt)
+}
+
 type PokemonResponse struct {
-	Data []PokemonAPICard `json:"data"`
+	Data       []PokemonAPICard `json:"data"`
+	Page       int              `json:"page"`
+	PageSize   int              `json:"pageSize"`
+	Count      int              `json:"count"`
+	TotalCount int              `json:"totalCount"`
 }
 
 type PokemonSet struct {
@@ -264,14 +556,26 @@ type PokemonSet struct {
 	PrintedTotal int    `json:"printedTotal"`
 	Total        int    `json:"total"`
 	ReleaseDate  string `json:"releaseDate"`
+	UpdatedAt    string `json:"updatedAt"`
+}
+
+// ParseUpdatedAt returns when the API last changed the set, reporting false if the
+// timestamp is missing or malformed
+func (s PokemonSet) ParseUpdatedAt() (time.Time, bool) {
+	updatedAt, err := time.Parse(pokemonSetTimeLayout, s.UpdatedAt)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return updatedAt, true
 }
 
 type PokemonAPICard struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
 	Set  struct {
-		ID   string `json:"id"`
-		Name string `json:"name"`
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		ReleaseDate string `json:"releaseDate"`
 	} `json:"set"`
 	Number string `json:"number"`
 	Rarity string `json:"rarity"`
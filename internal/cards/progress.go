@@ -0,0 +1,37 @@
+package cards
+
+import "context"
+
+// ProgressReporter receives the counters of a running import
+type ProgressReporter interface {
+	// AddCards records cards that were inserted, updated or skipped as unchanged
+	AddCards(inserted, updated, skipped int)
+	// SetBatches records how many of the import's batches, or sets, have been processed
+	SetBatches(processed, total int)
+	// RecordError records an error that did not stop the import
+	RecordError(err error)
+}
+
+type progressReporterKey struct{}
+
+// WithProgressReporter returns a context whose imports report their progress to reporter
+func WithProgressReporter(ctx context.Context, reporter ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressReporterKey{}, reporter)
+}
+
+// ProgressFromContext returns the reporter attached to the context, or one that discards everything
+func ProgressFromContext(ctx context.Context) ProgressReporter {
+	if reporter, ok := ctx.Value(progressReporterKey{}).(ProgressReporter); ok {
+		return reporter
+	}
+	return discardProgress{}
+}
+
+// discardProgress is used when no one is listening to an import
+type discardProgress struct{}
+
+func (discardProgress) AddCards(inserted, updated, skipped int) {}
+
+func (discardProgress) SetBatches(processed, total int) {}
+
+func (discardProgress) RecordError(err error) {}
@@ -0,0 +1,207 @@
+package cards
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownGame is returned when no importer is registered for a game
+var ErrUnknownGame = errors.New("no importer registered for game")
+
+// ErrNotConfigured is returned when an importer lacks configuration it needs, such as an API key
+var ErrNotConfigured = errors.New("importer is not configured")
+
+// Capability describes an optional feature of a card importer
+type Capability string
+
+const (
+	// CapabilityFullImport means the importer can import the complete card catalog of its game
+	CapabilityFullImport Capability = "FULL_IMPORT"
+	// CapabilitySingleSet means the importer can import one set on its own via SetImporter
+	CapabilitySingleSet Capability = "SINGLE_SET"
+	// CapabilityIncremental means the importer skips cards that have not changed since the last import
+	CapabilityIncremental Capability = "INCREMENTAL"
+)
+
+// SetImporter is implemented by importers that can import a single set
+type SetImporter interface {
+	ImportSet(ctx context.Context, setID string) error
+}
+
+// ConfigChecker is implemented by importers that need configuration, such as an API key,
+// before they can run
+type ConfigChecker interface {
+	// CheckConfig returns an error wrapping ErrNotConfigured if the importer cannot run
+	CheckConfig() error
+}
+
+// CheckConfigured reports whether an importer can run. Importers that need no configuration always can.
+func CheckConfigured(importer CardImporter) error {
+	if checker, ok := importer.(ConfigChecker); ok {
+		return checker.CheckConfig()
+	}
+	return nil
+}
+
+// ImporterRegistration describes a card importer and how to construct it
+type ImporterRegistration struct {
+	// Game is the game identifier stored on cards, such as "mtg"
+	Game string
+	// DisplayName is the human readable name of the game
+	DisplayName string
+	// DetailTable is the table holding the game-specific card details
+	DetailTable string
+	// Capabilities lists the optional features the importer supports
+	Capabilities []Capability
+	// New constructs the importer along with its detail store
+	New func(store *CardStore, db *sql.DB) CardImporter
+	// NewWithSource constructs the importer reading a local dump or stand-in endpoint
+	// instead of the live data. It is nil for importers that only read live data.
+	NewWithSource func(store *CardStore, db *sql.DB, source DataSource) CardImporter
+}
+
+// HasCapability reports whether the importer supports a capability
+func (r *ImporterRegistration) HasCapability(capability Capability) bool {
+	for _, c := range r.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// ImporterStatus describes a registered importer and whether it can currently run
+type ImporterStatus struct {
+	Game         string       `json:"game"`
+	DisplayName  string       `json:"displayName"`
+	Capabilities []Capability `json:"capabilities"`
+	Configured   bool         `json:"configured"`
+	// Reason explains why an unconfigured importer cannot run
+	Reason *string `json:"reason"`
+}
+
+// Registry holds the card importers known to the application
+type Registry struct {
+	mu            sync.RWMutex
+	registrations map[string]*ImporterRegistration
+}
+
+// NewRegistry creates a new, empty importer registry
+func NewRegistry() *Registry {
+	return &Registry{
+		registrations: make(map[string]*ImporterRegistration),
+	}
+}
+
+// DefaultRegistry is the registry the built-in importers register themselves with
+var DefaultRegistry = NewRegistry()
+
+// Register adds an importer to the default registry. It panics if the game is already registered.
+func Register(registration ImporterRegistration) {
+	DefaultRegistry.Register(registration)
+}
+
+// Register adds an importer to the registry. It panics if the game is already registered.
+func (r *Registry) Register(registration ImporterRegistration) {
+	if registration.Game == "" || registration.New == nil {
+		panic("cards: importer registration requires a game and a constructor")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.registrations[registration.Game]; exists {
+		panic(fmt.Sprintf("cards: importer for %s registered twice", registration.Game))
+	}
+	r.registrations[registration.Game] = &registration
+}
+
+// Lookup returns the registration for a game
+func (r *Registry) Lookup(game string) (*ImporterRegistration, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	registration, ok := r.registrations[game]
+	return registration, ok
+}
+
+// All returns every registration ordered by game identifier
+func (r *Registry) All() []*ImporterRegistration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	registrations := make([]*ImporterRegistration, 0, len(r.registrations))
+	for _, registration := range r.registrations {
+		registrations = append(registrations, registration)
+	}
+	sort.Slice(registrations, func(i, j int) bool {
+		return registrations[i].Game < registrations[j].Game
+	})
+	return registrations
+}
+
+// Games returns the identifiers of every registered game in order
+func (r *Registry) Games() []string {
+	registrations := r.All()
+	games := make([]string, len(registrations))
+	for i, registration := range registrations {
+		games[i] = registration.Game
+	}
+	return games
+}
+
+// NewImporter constructs the importer registered for a game
+func (r *Registry) NewImporter(game string, store *CardStore, db *sql.DB) (CardImporter, error) {
+	registration, ok := r.Lookup(game)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownGame, game)
+	}
+	return registration.New(store, db), nil
+}
+
+// NewImporterWithSource constructs the importer registered for a game reading from source
+func (r *Registry) NewImporterWithSource(game string, store *CardStore, db *sql.DB, source DataSource) (CardImporter, error) {
+	registration, ok := r.Lookup(game)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownGame, game)
+	}
+	if registration.NewWithSource == nil {
+		return nil, fmt.Errorf("%s importer cannot read from a local source", registration.DisplayName)
+	}
+	return registration.NewWithSource(store, db, source), nil
+}
+
+// NewImporters constructs every registered importer in order
+func (r *Registry) NewImporters(store *CardStore, db *sql.DB) []CardImporter {
+	registrations := r.All()
+	importers := make([]CardImporter, len(registrations))
+	for i, registration := range registrations {
+		importers[i] = registration.New(store, db)
+	}
+	return importers
+}
+
+// Statuses reports, for every registered importer in order, whether it is configured to run
+func (r *Registry) Statuses(store *CardStore, db *sql.DB) []*ImporterStatus {
+	registrations := r.All()
+	statuses := make([]*ImporterStatus, len(registrations))
+	for i, registration := range registrations {
+		status := &ImporterStatus{
+			Game:         registration.Game,
+			DisplayName:  registration.DisplayName,
+			Capabilities: registration.Capabilities,
+			Configured:   true,
+		}
+		if err := CheckConfigured(registration.New(store, db)); err != nil {
+			reason := err.Error()
+			status.Configured = false
+			status.Reason = &reason
+		}
+		statuses[i] = status
+	}
+	return statuses
+}
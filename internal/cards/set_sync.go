@@ -0,0 +1,71 @@
+package cards
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SetSyncStore remembers the upstream revision of each imported set, so unchanged sets
+// can be skipped on the next run
+type SetSyncStore struct {
+	db *sql.DB
+}
+
+// NewSetSyncStore creates a new set sync store
+func NewSetSyncStore(db *sql.DB) *SetSyncStore {
+	return &SetSyncStore{db: db}
+}
+
+// List returns the last imported upstream revision of every synced set of a game, keyed by set code
+func (s *SetSyncStore) List(ctx context.Context, game string) (map[string]time.Time, error) {
+	query := `
+		SELECT set_code, source_updated_at
+		FROM import_set_syncs
+		WHERE game = $1
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, game)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list set syncs: %w", err)
+	}
+	defer rows.Close()
+
+	synced := make(map[string]time.Time)
+	for rows.Next() {
+		var setCode string
+		var updatedAt time.Time
+		if err := rows.Scan(&setCode, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan set sync: %w", err)
+		}
+		synced[setCode] = updatedAt
+	}
+
+	return synced, rows.Err()
+}
+
+// Save records that a set has been imported as of its upstream revision
+func (s *SetSyncStore) Save(ctx context.Context, game, setCode string, sourceUpdatedAt time.Time) error {
+	query := `
+		INSERT INTO import_set_syncs (game, set_code, source_updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (game, set_code) DO UPDATE SET
+			source_updated_at = EXCLUDED.source_updated_at
+	`
+
+	if _, err := s.db.ExecContext(ctx, query, game, setCode, sourceUpdatedAt); err != nil {
+		return fmt.Errorf("failed to save set sync: %w", err)
+	}
+
+	return nil
+}
+
+// Clear forgets every synced set of a game, so its next import re-imports them all
+func (s *SetSyncStore) Clear(ctx context.Context, game string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM import_set_syncs WHERE game = $1`, game); err != nil {
+		return fmt.Errorf("failed to clear set syncs: %w", err)
+	}
+
+	return nil
+}
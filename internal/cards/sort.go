@@ -0,0 +1,265 @@
+package cards
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shiftregister-vg/card-craft/internal/models"
+)
+
+// Card sort fields, named as in the CardSortField GraphQL enum. Only these can be sorted by,
+// so client input never reaches the ORDER BY clause.
+const (
+	SortRelevance   = "RELEVANCE"
+	SortName        = "NAME"
+	SortSet         = "SET"
+	SortNumber      = "NUMBER"
+	SortRarity      = "RARITY"
+	SortReleaseDate = "RELEASE_DATE"
+	SortPrice       = "PRICE"
+)
+
+// Sort orders, named as in the SortOrder GraphQL enum
+const (
+	SortAsc  = "ASC"
+	SortDesc = "DESC"
+)
+
+// ErrInvalidSort is returned for a sort field or order outside the whitelist
+var ErrInvalidSort = errors.New("invalid sort")
+
+// PageOptions selects a sorted page of cards. SortBy and SortOrder take the sort constants
+// above; After is the cursor of the last card of the previous page.
+type PageOptions struct {
+	SortBy    string
+	SortOrder string
+	First     int
+	After     string
+}
+
+// CardPage is one page of sorted cards. Cursors holds the cursor of each card, which can be
+// passed back as After to continue past it under the same sort.
+type CardPage struct {
+	Cards       []*models.Card
+	Cursors     []string
+	HasNextPage bool
+	// TotalCount is the number of matching cards across every page, where it is counted
+	TotalCount int
+}
+
+// sortKey is one expression of an ORDER BY clause
+type sortKey struct {
+	expr string
+	desc bool
+}
+
+// cardSort is a validated sort, expanded into the keys that give every card a unique position
+type cardSort struct {
+	field string
+	order string
+	keys  []sortKey
+	// term is the searched name that relevance is ranked against
+	term string
+}
+
+// newCardSort validates a sort field and order. An empty field sorts by relevance when rank is
+// set and by name otherwise; an empty order is ascending, except for relevance, which puts the
+// best matches first. rank is the SQL expression scoring a card against the searched name.
+func newCardSort(field, order, rank string) (*cardSort, error) {
+	field = strings.ToUpper(strings.TrimSpace(field))
+	order = strings.ToUpper(strings.TrimSpace(order))
+
+	if field == "" {
+		field = SortName
+		if rank != "" {
+			field = SortRelevance
+		}
+	}
+	if field == SortRelevance && rank == "" {
+		// Without a searched name every card is equally relevant
+		field = SortName
+	}
+
+	switch order {
+	case "":
+		order = SortAsc
+		if field == SortRelevance {
+			order = SortDesc
+		}
+	case SortAsc, SortDesc:
+	default:
+		return nil, fmt.Errorf("%w order %q", ErrInvalidSort, order)
+	}
+	desc := order == SortDesc
+
+	// Ties are broken in a fixed direction and end with the card ID, so the keys are unique
+	var keys []sortKey
+	switch field {
+	case SortRelevance:
+		keys = []sortKey{{rank, desc}, {"c.name", false}}
+	case SortName:
+		keys = []sortKey{{"c.name", desc}, {"c.set_code", false}, {"c.number", false}}
+	case SortSet:
+		keys = []sortKey{{"c.set_code", desc}, {"c.number", desc}}
+	case SortNumber:
+		keys = []sortKey{{"c.number", desc}, {"c.set_code", false}}
+	case SortRarity:
+		keys = []sortKey{{"c.rarity", desc}, {"c.name", false}}
+	case SortReleaseDate:
+		// Cards without a release date come last in either order
+		keys = []sortKey{{"(c.released_at IS NULL)", false}, {"COALESCE(c.released_at, DATE '1970-01-01')", desc}, {"c.name", false}}
+	case SortPrice:
+		// Cards without a price come last in either order
+		keys = []sortKey{{"(c.price_usd IS NULL)", false}, {"COALESCE(c.price_usd, 0)", desc}, {"c.name", false}}
+	default:
+		return nil, fmt.Errorf("%w field %q", ErrInvalidSort, field)
+	}
+	keys = append(keys, sortKey{"c.id", false})
+
+	return &cardSort{field: field, order: order, keys: keys}, nil
+}
+
+// orderBy returns the ORDER BY clause of the sort
+func (s *cardSort) orderBy() string {
+	parts := make([]string, len(s.keys))
+	for i, key := range s.keys {
+		direction := "ASC"
+		if key.desc {
+			direction = "DESC"
+		}
+		parts[i] = key.expr + " " + direction
+	}
+	return " ORDER BY " + strings.Join(parts, ", ")
+}
+
+// selectKeys returns the sort keys as text columns, so the cursor of each card can be built
+func (s *cardSort) selectKeys() string {
+	parts := make([]string, len(s.keys))
+	for i, key := range s.keys {
+		parts[i] = "(" + key.expr + ")::text"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// after returns the condition matching the cards that follow the position of a cursor, whose
+// key values are passed as the given placeholders
+func (s *cardSort) after(placeholders []string) string {
+	alternatives := make([]string, len(s.keys))
+	for i, key := range s.keys {
+		var conditions []string
+		for j := 0; j < i; j++ {
+			conditions = append(conditions, fmt.Sprintf("%s = %s", s.keys[j].expr, placeholders[j]))
+		}
+		op := ">"
+		if key.desc {
+			op = "<"
+		}
+		conditions = append(conditions, fmt.Sprintf("%s %s %s", key.expr, op, placeholders[i]))
+		alternatives[i] = "(" + strings.Join(conditions, " AND ") + ")"
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")"
+}
+
+// cardCursor is the content of an opaque pagination cursor: the sort it was created under and
+// the values of the sort keys of the card it points past
+type cardCursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+}
+
+// signature identifies the sort in a cursor. Relevance scores depend on the searched name, so a
+// relevance cursor also carries a hash of the name and cannot continue a different search.
+func (s *cardSort) signature() string {
+	signature := s.field + ":" + s.order
+	if s.field == SortRelevance {
+		sum := sha256.Sum256([]byte(strings.ToLower(s.term)))
+		signature += ":" + hex.EncodeToString(sum[:8])
+	}
+	return signature
+}
+
+// cursor encodes the position of a card under this sort
+func (s *cardSort) cursor(values []string) string {
+	raw, _ := json.Marshal(cardCursor{Sort: s.signature(), Values: values})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor reads a cursor, rejecting those created under a different sort
+func (s *cardSort) decodeCursor(cursor string) ([]string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c cardCursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.Sort != s.signature() || len(c.Values) != len(s.keys) {
+		return nil, ErrInvalidCursor
+	}
+
+	return c.Values, nil
+}
+
+// findPage selects up to first cards under a sort. from holds the FROM and WHERE clauses of
+// the query, with its values in args. With an after cursor the page starts past that card;
+// otherwise offset cards are skipped.
+func (s *CardStore) findPage(ctx context.Context, from string, args []interface{}, sort *cardSort, first int, after string, offset int) (*CardPage, error) {
+	args = args[:len(args):len(args)]
+	if after != "" {
+		values, err := sort.decodeCursor(after)
+		if err != nil {
+			return nil, err
+		}
+		placeholders := make([]string, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		from += " AND " + sort.after(placeholders)
+		offset = 0
+	}
+
+	// Fetch one extra card to learn whether another page follows
+	args = append(args, first+1, offset)
+	query := fmt.Sprintf("SELECT %s, %s%s%s LIMIT $%d OFFSET $%d",
+		cardColumns, sort.selectKeys(), from, sort.orderBy(), len(args)-1, len(args))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search cards: %w", err)
+	}
+	defer rows.Close()
+
+	page := &CardPage{Cards: []*models.Card{}, Cursors: []string{}}
+	for rows.Next() {
+		values := make([]string, len(sort.keys))
+		dest := make([]interface{}, len(values))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		card, err := scanCard(rows, dest...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan card: %w", err)
+		}
+		page.Cards = append(page.Cards, card)
+		page.Cursors = append(page.Cursors, sort.cursor(values))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search cards: %w", err)
+	}
+
+	if len(page.Cards) > first {
+		page.Cards = page.Cards[:first]
+		page.Cursors = page.Cursors[:first]
+		page.HasNextPage = true
+	}
+	return page, nil
+}
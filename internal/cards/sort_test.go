@@ -0,0 +1,74 @@
+package cards
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCardSortCursors(t *testing.T) {
+	newSort := func(field, order, term string) *cardSort {
+		t.Helper()
+		rank := ""
+		if term != "" {
+			rank = "rank($3)"
+		}
+		sort, err := newCardSort(field, order, rank)
+		if err != nil {
+			t.Fatalf("newCardSort(%q, %q): %v", field, order, err)
+		}
+		sort.term = term
+		return sort
+	}
+
+	relevance := newSort("", "", "Charizard")
+	values := []string{"10.500000", "Charizard", "4f1c6c4e-8f2f-4d1a-9a55-0f7c1b2b1e11"}
+	cursor := relevance.cursor(values)
+
+	tests := []struct {
+		name    string
+		sort    *cardSort
+		cursor  string
+		wantErr bool
+	}{
+		{name: "same search", sort: newSort("", "", "Charizard"), cursor: cursor},
+		{name: "name differs only in case", sort: newSort("", "", "charizard"), cursor: cursor},
+		{name: "different searched name", sort: newSort("", "", "Pikachu"), cursor: cursor, wantErr: true},
+		{name: "different order", sort: newSort(SortRelevance, SortAsc, "Charizard"), cursor: cursor, wantErr: true},
+		{name: "different field", sort: newSort(SortName, "", ""), cursor: cursor, wantErr: true},
+		{name: "not base64", sort: relevance, cursor: "not a cursor!", wantErr: true},
+		{name: "not JSON", sort: relevance, cursor: "bm90IGpzb24", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.sort.decodeCursor(tt.cursor)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCursor) {
+					t.Fatalf("decodeCursor() error = %v, want ErrInvalidCursor", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeCursor(): %v", err)
+			}
+			if !reflect.DeepEqual(got, values) {
+				t.Errorf("decodeCursor() = %v, want %v", got, values)
+			}
+		})
+	}
+}
+
+func TestCardSortIgnoresTermOutsideRelevance(t *testing.T) {
+	byName, err := newCardSort(SortName, "", "rank($3)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName.term = "Charizard"
+	cursor := byName.cursor([]string{"Charizard", "base1", "4", "4f1c6c4e-8f2f-4d1a-9a55-0f7c1b2b1e11"})
+
+	byName.term = "Pikachu"
+	if _, err := byName.decodeCursor(cursor); err != nil {
+		t.Errorf("a name sort cursor was rejected for another search: %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package cards
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// DataSource provides the raw card data an importer reads from. Importers that accept a
+// DataSource can run against a live endpoint, a local dump or an httptest stand-in alike.
+type DataSource interface {
+	// Open returns a reader over the card data. The caller must close it.
+	Open(ctx context.Context) (io.ReadCloser, error)
+	// Name identifies the data, such as its URL or path
+	Name() string
+}
+
+// HTTPSource reads card data from a published URL
+type HTTPSource struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewHTTPSource creates a new HTTP data source for the given URL
+func NewHTTPSource(url string) *HTTPSource {
+	return &HTTPSource{
+		URL: url,
+		Client: &http.Client{
+			Timeout: 5 * time.Minute,
+		},
+	}
+}
+
+// Open implements the DataSource interface
+func (s *HTTPSource) Open(ctx context.Context) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "CardCraftApp/1.0")
+	req.Header.Set("Accept", "application/json;q=0.9,*/*;q=0.8")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", s.URL, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch %s: status %d, body: %s", s.URL, resp.StatusCode, string(body))
+	}
+
+	return resp.Body, nil
+}
+
+// Name implements the DataSource interface
+func (s *HTTPSource) Name() string {
+	return s.URL
+}
+
+// FileSource reads card data from a local file, such as a downloaded dump or a test fixture
+type FileSource struct {
+	Path string
+}
+
+// NewFileSource creates a new file data source for the given path
+func NewFileSource(path string) *FileSource {
+	return &FileSource{Path: path}
+}
+
+// Open implements the DataSource interface
+func (s *FileSource) Open(ctx context.Context) (io.ReadCloser, error) {
+	file, err := os.Open(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", s.Path, err)
+	}
+	return file, nil
+}
+
+// Name implements the DataSource interface
+func (s *FileSource) Name() string {
+	return s.Path
+}
+
+// DumpFileName returns the name of a game's card dump inside a source directory
+func DumpFileName(game string) string {
+	return game + ".json"
+}
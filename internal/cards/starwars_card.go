@@ -0,0 +1,147 @@
+package cards
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// StarWarsCard represents Star Wars: Unlimited-specific card data
+type StarWarsCard struct {
+	ID           string    `json:"id"`
+	CardID       string    `json:"card_id"`
+	Subtitle     string    `json:"subtitle"`
+	CardType     string    `json:"cardType"`
+	Aspects      []string  `json:"aspects"`
+	Arenas       []string  `json:"arenas"`
+	Traits       []string  `json:"traits"`
+	Keywords     []string  `json:"keywords"`
+	Cost         *int      `json:"cost"`
+	Power        *int      `json:"power"`
+	HP           *int      `json:"hp"`
+	Unique       bool      `json:"unique"`
+	FrontText    string    `json:"frontText"`
+	EpicAction   string    `json:"epicAction"`
+	DoubleSided  bool      `json:"doubleSided"`
+	BackText     string    `json:"backText"`
+	BackImageURL string    `json:"backImageUrl"`
+	VariantType  string    `json:"variantType"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// StarWarsCardStore handles database operations for Star Wars: Unlimited-specific card data
+type StarWarsCardStore struct {
+	db *sql.DB
+}
+
+// NewStarWarsCardStore creates a new Star Wars: Unlimited card store
+func NewStarWarsCardStore(db *sql.DB) *StarWarsCardStore {
+	return &StarWarsCardStore{db: db}
+}
+
+// Upsert creates the Star Wars: Unlimited details for a card or replaces the existing ones
+func (s *StarWarsCardStore) Upsert(ctx context.Context, card *StarWarsCard) error {
+	query := `
+		INSERT INTO starwars_cards (
+			card_id, subtitle, card_type, aspects, arenas, traits, keywords, cost, power, hp,
+			is_unique, front_text, epic_action, double_sided, back_text, back_image_url, variant_type
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		ON CONFLICT (card_id) DO UPDATE SET
+			subtitle = EXCLUDED.subtitle,
+			card_type = EXCLUDED.card_type,
+			aspects = EXCLUDED.aspects,
+			arenas = EXCLUDED.arenas,
+			traits = EXCLUDED.traits,
+			keywords = EXCLUDED.keywords,
+			cost = EXCLUDED.cost,
+			power = EXCLUDED.power,
+			hp = EXCLUDED.hp,
+			is_unique = EXCLUDED.is_unique,
+			front_text = EXCLUDED.front_text,
+			epic_action = EXCLUDED.epic_action,
+			double_sided = EXCLUDED.double_sided,
+			back_text = EXCLUDED.back_text,
+			back_image_url = EXCLUDED.back_image_url,
+			variant_type = EXCLUDED.variant_type
+		RETURNING id, created_at, updated_at
+	`
+
+	err := s.db.QueryRowContext(ctx, query,
+		card.CardID,
+		card.Subtitle,
+		card.CardType,
+		pq.Array(card.Aspects),
+		pq.Array(card.Arenas),
+		pq.Array(card.Traits),
+		pq.Array(card.Keywords),
+		card.Cost,
+		card.Power,
+		card.HP,
+		card.Unique,
+		card.FrontText,
+		card.EpicAction,
+		card.DoubleSided,
+		card.BackText,
+		card.BackImageURL,
+		card.VariantType,
+	).Scan(&card.ID, &card.CreatedAt, &card.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert star wars card: %w", err)
+	}
+
+	return nil
+}
+
+// FindByCardID finds the Star Wars: Unlimited details of a card by its card ID
+func (s *StarWarsCardStore) FindByCardID(ctx context.Context, cardID string) (*StarWarsCard, error) {
+	query := `
+		SELECT id, card_id, COALESCE(subtitle, ''), card_type, aspects, arenas, traits, keywords,
+			cost, power, hp, is_unique, COALESCE(front_text, ''), COALESCE(epic_action, ''),
+			double_sided, COALESCE(back_text, ''), COALESCE(back_image_url, ''),
+			COALESCE(variant_type, ''), created_at, updated_at
+		FROM starwars_cards
+		WHERE card_id = $1
+	`
+
+	var card StarWarsCard
+	var cost, power, hp sql.NullInt64
+
+	err := s.db.QueryRowContext(ctx, query, cardID).Scan(
+		&card.ID,
+		&card.CardID,
+		&card.Subtitle,
+		&card.CardType,
+		pq.Array(&card.Aspects),
+		pq.Array(&card.Arenas),
+		pq.Array(&card.Traits),
+		pq.Array(&card.Keywords),
+		&cost,
+		&power,
+		&hp,
+		&card.Unique,
+		&card.FrontText,
+		&card.EpicAction,
+		&card.DoubleSided,
+		&card.BackText,
+		&card.BackImageURL,
+		&card.VariantType,
+		&card.CreatedAt,
+		&card.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find star wars card: %w", err)
+	}
+
+	card.Cost = nullIntPtr(cost)
+	card.Power = nullIntPtr(power)
+	card.HP = nullIntPtr(hp)
+
+	return &card, nil
+}
@@ -1,21 +1,76 @@
 package cards
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// StarWarsAPIURL is the base URL of the SWU-DB card API
+const StarWarsAPIURL = "https://api.swu-db.com"
+
+// StarWarsSet describes a released Star Wars: Unlimited set
+type StarWarsSet struct {
+	Code string
+	Name string
+}
+
+// StarWarsSets lists the released Star Wars: Unlimited sets in release order
+var StarWarsSets = []StarWarsSet{
+	{Code: "SOR", Name: "Spark of Rebellion"},
+	{Code: "SHD", Name: "Shadows of the Galaxy"},
+	{Code: "TWI", Name: "Twilight of the Republic"},
+	{Code: "JTL", Name: "Jump to Lightspeed"},
+	{Code: "LOF", Name: "Legends of the Force"},
+	{Code: "SEC", Name: "Secrets of Power"},
+}
+
+func init() {
+	Register(ImporterRegistration{
+		Game:         "starwars",
+		DisplayName:  "Star Wars: Unlimited",
+		DetailTable:  "starwars_cards",
+		Capabilities: []Capability{CapabilityFullImport, CapabilitySingleSet},
+		New: func(store *CardStore, db *sql.DB) CardImporter {
+			return NewStarWarsImporter(store, NewStarWarsCardStore(db))
+		},
+		NewWithSource: func(store *CardStore, db *sql.DB, source DataSource) CardImporter {
+			return NewStarWarsImporterWithSource(store, NewStarWarsCardStore(db), source)
+		},
+	})
+}
+
 // StarWarsImporter handles importing Star Wars: Unlimited card data
 type StarWarsImporter struct {
 	*BaseImporter
+	starWarsStore *StarWarsCardStore
+	// source holds a single card dump covering every set, such as a local fixture.
+	// When nil, each set is fetched from the SWU-DB API.
+	source DataSource
 }
 
-// NewStarWarsImporter creates a new Star Wars: Unlimited importer
-func NewStarWarsImporter(store *CardStore) *StarWarsImporter {
+// NewStarWarsImporter creates a new Star Wars: Unlimited importer that reads from the SWU-DB API
+func NewStarWarsImporter(store *CardStore, starWarsStore *StarWarsCardStore) *StarWarsImporter {
 	return &StarWarsImporter{
-		BaseImporter: NewBaseImporter(store, "starwars"),
+		BaseImporter:  NewBaseImporter(store, "starwars"),
+		starWarsStore: starWarsStore,
 	}
 }
 
+// NewStarWarsImporterWithSource creates a new Star Wars: Unlimited importer that reads an
+// SWU-DB formatted card dump from the given source instead of the API
+func NewStarWarsImporterWithSource(store *CardStore, starWarsStore *StarWarsCardStore, source DataSource) *StarWarsImporter {
+	importer := NewStarWarsImporter(store, starWarsStore)
+	importer.source = source
+	return importer
+}
+
 // Import implements the Importer interface
 func (i *StarWarsImporter) Import(ctx context.Context, store *CardStore) error {
 	return i.ImportLatestSets(ctx)
@@ -23,14 +78,231 @@ func (i *StarWarsImporter) Import(ctx context.Context, store *CardStore) error {
 
 // ImportSet imports all cards from a specific set
 func (i *StarWarsImporter) ImportSet(ctx context.Context, setID string) error {
-	// TODO: Implement Star Wars: Unlimited set import
-	// This will need to be implemented once we have access to the Star Wars: Unlimited API or data source
-	return nil
+	setCode := strings.ToUpper(setID)
+
+	apiCards, err := i.fetchCards(ctx, i.sourceForSet(setCode))
+	if err != nil {
+		return err
+	}
+
+	return i.importSet(ctx, setCode, apiCards)
 }
 
-// ImportLatestSets imports cards from the latest Star Wars: Unlimited sets
+// ImportLatestSets imports cards from every released Star Wars: Unlimited set
 func (i *StarWarsImporter) ImportLatestSets(ctx context.Context) error {
-	// TODO: Implement Star Wars: Unlimited latest sets import
-	// This will need to be implemented once we have access to the Star Wars: Unlimited API or data source
+	startTime := time.Now()
+	log.Printf("Starting Star Wars: Unlimited card import process")
+	progress := ProgressFromContext(ctx)
+
+	if i.source != nil {
+		apiCards, err := i.fetchCards(ctx, i.source)
+		if err != nil {
+			return err
+		}
+
+		// Import the sets in the order they appear in the dump
+		var setCodes []string
+		seen := make(map[string]bool)
+		for _, apiCard := range apiCards {
+			code := strings.ToUpper(apiCard.Set)
+			if !seen[code] {
+				seen[code] = true
+				setCodes = append(setCodes, code)
+			}
+		}
+
+		log.Printf("Found %d sets to import", len(setCodes))
+
+		progress.SetBatches(0, len(setCodes))
+		for setIndex, code := range setCodes {
+			log.Printf("Importing set %d/%d: %s", setIndex+1, len(setCodes), code)
+			if err := i.importSet(ctx, code, apiCards); err != nil {
+				return fmt.Errorf("failed to import set %s: %w", code, err)
+			}
+			progress.SetBatches(setIndex+1, len(setCodes))
+		}
+	} else {
+		log.Printf("Found %d sets to import", len(StarWarsSets))
+
+		progress.SetBatches(0, len(StarWarsSets))
+		for setIndex, set := range StarWarsSets {
+			log.Printf("Importing set %d/%d: %s (%s)", setIndex+1, len(StarWarsSets), set.Name, set.Code)
+			if err := i.ImportSet(ctx, set.Code); err != nil {
+				return fmt.Errorf("failed to import set %s: %w", set.Code, err)
+			}
+			progress.SetBatches(setIndex+1, len(StarWarsSets))
+
+			// Add a small delay between sets to be nice to the API
+			time.Sleep(500 * time.Millisecond)
+		}
+	}
+
+	log.Printf("Star Wars: Unlimited import completed in %s", time.Since(startTime))
+	return nil
+}
+
+// sourceForSet returns the data source holding the cards of a set
+func (i *StarWarsImporter) sourceForSet(setCode string) DataSource {
+	if i.source != nil {
+		return i.source
+	}
+	return NewHTTPSource(fmt.Sprintf("%s/cards/%s", StarWarsAPIURL, strings.ToLower(setCode)))
+}
+
+// fetchCards reads and decodes an SWU-DB card list from a source
+func (i *StarWarsImporter) fetchCards(ctx context.Context, source DataSource) ([]StarWarsAPICard, error) {
+	reader, err := source.Open(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open star wars card data: %w", err)
+	}
+	defer reader.Close()
+
+	var response StarWarsAPIResponse
+	if err := json.NewDecoder(reader).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode star wars card data: %w", err)
+	}
+
+	return response.Data, nil
+}
+
+// importSet creates or updates every card of one set
+func (i *StarWarsImporter) importSet(ctx context.Context, setCode string, apiCards []StarWarsAPICard) error {
+	setName := setCode
+	for _, set := range StarWarsSets {
+		if set.Code == setCode {
+			setName = set.Name
+			break
+		}
+	}
+
+	var total int
+	for _, apiCard := range apiCards {
+		if !strings.EqualFold(apiCard.Set, setCode) {
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		card := i.NewCard()
+		card.Name = apiCard.Name
+		if apiCard.Subtitle != "" {
+			card.Name = apiCard.Name + ", " + apiCard.Subtitle
+		}
+		card.SetCode = setCode
+		card.SetName = setName
+		card.Number = apiCard.Number
+		card.Rarity = apiCard.Rarity
+		card.ImageUrl = apiCard.FrontArt
+
+		if err := i.CreateOrUpdateCard(ctx, card); err != nil {
+			return fmt.Errorf("failed to save card %s (%s): %w", card.Name, card.Number, err)
+		}
+
+		starWarsCard := &StarWarsCard{
+			CardID:       card.ID.String(),
+			Subtitle:     apiCard.Subtitle,
+			CardType:     apiCard.Type,
+			Aspects:      apiCard.Aspects,
+			Arenas:       apiCard.Arenas,
+			Traits:       apiCard.Traits,
+			Keywords:     apiCard.Keywords,
+			Cost:         starWarsStat(apiCard, "cost", apiCard.Cost),
+			Power:        starWarsStat(apiCard, "power", apiCard.Power),
+			HP:           starWarsStat(apiCard, "HP", apiCard.HP),
+			Unique:       apiCard.Unique,
+			FrontText:    apiCard.FrontText,
+			EpicAction:   apiCard.EpicAction,
+			DoubleSided:  apiCard.DoubleSided,
+			BackText:     apiCard.BackText,
+			BackImageURL: apiCard.BackArt,
+			VariantType:  apiCard.VariantType,
+		}
+
+		if err := i.starWarsStore.Upsert(ctx, starWarsCard); err != nil {
+			return fmt.Errorf("failed to save star wars details for %s (%s): %w", card.Name, card.Number, err)
+		}
+
+		total++
+	}
+
+	log.Printf("Set %s import completed: %d cards processed", setCode, total)
 	return nil
 }
+
+// StarWarsAPIResponse is the top-level structure of an SWU-DB card list
+type StarWarsAPIResponse struct {
+	TotalCards int               `json:"total_cards"`
+	Data       []StarWarsAPICard `json:"data"`
+}
+
+type StarWarsAPICard struct {
+	Set         string         `json:"Set"`
+	Number      string         `json:"Number"`
+	Name        string         `json:"Name"`
+	Subtitle    string         `json:"Subtitle"`
+	Type        string         `json:"Type"`
+	Aspects     []string       `json:"Aspects"`
+	Traits      []string       `json:"Traits"`
+	Arenas      []string       `json:"Arenas"`
+	Keywords    []string       `json:"Keywords"`
+	Cost        StarWarsNumber `json:"Cost"`
+	Power       StarWarsNumber `json:"Power"`
+	HP          StarWarsNumber `json:"HP"`
+	FrontText   string         `json:"FrontText"`
+	EpicAction  string         `json:"EpicAction"`
+	DoubleSided bool           `json:"DoubleSided"`
+	BackText    string         `json:"BackText"`
+	Rarity      string         `json:"Rarity"`
+	Unique      bool           `json:"Unique"`
+	Artist      string         `json:"Artist"`
+	VariantType string         `json:"VariantType"`
+	FrontArt    string         `json:"FrontArt"`
+	BackArt     string         `json:"BackArt"`
+}
+
+// StarWarsNumber is a card stat that SWU-DB reports as either a number or a numeric string.
+// Missing or blank values are left unset, and so are values that are not numbers, such as
+// "X" for a cost that depends on the game, which are kept in Invalid to be reported.
+type StarWarsNumber struct {
+	Value   int
+	Valid   bool
+	Invalid string
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. A stat that is not a number leaves
+// the stat unset rather than failing the decode of every card of the set.
+func (n *StarWarsNumber) UnmarshalJSON(data []byte) error {
+	raw := strings.TrimSpace(string(bytes.Trim(data, `"`)))
+	if raw == "" || raw == "null" || raw == "-" {
+		*n = StarWarsNumber{}
+		return nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		*n = StarWarsNumber{Invalid: raw}
+		return nil
+	}
+
+	*n = StarWarsNumber{Value: value, Valid: true}
+	return nil
+}
+
+// starWarsStat returns a stat of a card for storing, logging values that are not numbers
+func starWarsStat(apiCard StarWarsAPICard, stat string, n StarWarsNumber) *int {
+	if n.Invalid != "" {
+		log.Printf("Warning: %s (%s %s) has a non-numeric %s %q, leaving it unset", apiCard.Name, apiCard.Set, apiCard.Number, stat, n.Invalid)
+	}
+	return n.Ptr()
+}
+
+// Ptr returns the stat as an int pointer, or nil when it is unset
+func (n StarWarsNumber) Ptr() *int {
+	if !n.Valid {
+		return nil
+	}
+	v := n.Value
+	return &v
+}
@@ -0,0 +1,64 @@
+package cards
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStarWarsNumberUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		json    string
+		want    StarWarsNumber
+		wantPtr bool
+	}{
+		{json: `3`, want: StarWarsNumber{Value: 3, Valid: true}, wantPtr: true},
+		{json: `"4"`, want: StarWarsNumber{Value: 4, Valid: true}, wantPtr: true},
+		{json: `"0"`, want: StarWarsNumber{Value: 0, Valid: true}, wantPtr: true},
+		{json: `" 5 "`, want: StarWarsNumber{Value: 5, Valid: true}, wantPtr: true},
+		{json: `null`, want: StarWarsNumber{}},
+		{json: `""`, want: StarWarsNumber{}},
+		{json: `"-"`, want: StarWarsNumber{}},
+		{json: `"X"`, want: StarWarsNumber{Invalid: "X"}},
+		{json: `"2+"`, want: StarWarsNumber{Invalid: "2+"}},
+		{json: `1.5`, want: StarWarsNumber{Invalid: "1.5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			var got StarWarsNumber
+			if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+				t.Fatalf("Unmarshal(%s): %v", tt.json, err)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal(%s) = %+v, want %+v", tt.json, got, tt.want)
+			}
+			if ptr := got.Ptr(); (ptr != nil) != tt.wantPtr || (ptr != nil && *ptr != tt.want.Value) {
+				t.Errorf("Ptr() = %v, want set: %v", ptr, tt.wantPtr)
+			}
+		})
+	}
+}
+
+func TestStarWarsCardsDecodeWithNonNumericStats(t *testing.T) {
+	data := `[
+		{"Set": "SOR", "Number": "001", "Name": "Director Krennic", "Cost": "X", "Power": 2, "HP": "7"},
+		{"Set": "SOR", "Number": "002", "Name": "Iden Versio", "Cost": 5, "Power": "-", "HP": null}
+	]`
+
+	var cards []StarWarsAPICard
+	if err := json.Unmarshal([]byte(data), &cards); err != nil {
+		t.Fatalf("one card's non-numeric stat failed the whole decode: %v", err)
+	}
+	if len(cards) != 2 {
+		t.Fatalf("decoded %d cards, want 2", len(cards))
+	}
+	if starWarsStat(cards[0], "cost", cards[0].Cost) != nil {
+		t.Errorf("a cost of X was stored as a number")
+	}
+	if hp := starWarsStat(cards[0], "HP", cards[0].HP); hp == nil || *hp != 7 {
+		t.Errorf("HP = %v, want 7", hp)
+	}
+	if cost := starWarsStat(cards[1], "cost", cards[1].Cost); cost == nil || *cost != 5 {
+		t.Errorf("cost = %v, want 5", cost)
+	}
+}
@@ -1,7 +1,9 @@
 package cards
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -10,318 +12,336 @@ import (
 	"github.com/shiftregister-vg/card-craft/internal/types"
 )
 
-// CardStore handles database operations for cards
+// ErrInvalidCursor is returned when a pagination cursor was not created by this store
+var ErrInvalidCursor = errors.New("invalid cursor format")
+
+// cardColumns are the columns of the cards table in the order scanCard reads them
+const cardColumns = `c.id, c.name, c.game, c.set_code, c.set_name, c.number, c.rarity, c.image_url, c.created_at, c.updated_at, c.released_at, c.price_usd`
+
+// CardStore handles database operations for cards. It is the only place cards are read from
+// or written to; the game-specific stores hold the details that hang off each card.
 type CardStore struct {
 	db *sql.DB
 }
 
+// gameKey normalizes a game as it is stored on cards. Games are compared with = rather than
+// LOWER() so queries can use the indexes that lead with the game.
+func gameKey(game string) string {
+	return strings.ToLower(strings.TrimSpace(game))
+}
+
 // NewCardStore creates a new card store
 func NewCardStore(db *sql.DB) *CardStore {
 	return &CardStore{db: db}
 }
 
+// scanCard reads a card selected with cardColumns, followed by any extra columns
+func scanCard(row interface{ Scan(...interface{}) error }, extra ...interface{}) (*models.Card, error) {
+	card := &models.Card{}
+	var imageURL sql.NullString
+	var releasedAt sql.NullTime
+	var price sql.NullFloat64
+	dest := append([]interface{}{
+		&card.ID,
+		&card.Name,
+		&card.Game,
+		&card.SetCode,
+		&card.SetName,
+		&card.Number,
+		&card.Rarity,
+		&imageURL,
+		&card.CreatedAt,
+		&card.UpdatedAt,
+		&releasedAt,
+		&price,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	card.ImageUrl = imageURL.String
+	if releasedAt.Valid {
+		card.ReleasedAt = &releasedAt.Time
+	}
+	if price.Valid {
+		card.PriceUsd = &price.Float64
+	}
+	return card, nil
+}
+
+// queryCards runs a query selecting cardColumns and returns the cards it finds
+func (s *CardStore) queryCards(ctx context.Context, query string, args ...interface{}) ([]*models.Card, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cards := []*models.Card{}
+	for rows.Next() {
+		card, err := scanCard(rows)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, rows.Err()
+}
+
 // Create inserts a new card into the database
-func (s *CardStore) Create(card *types.Card) error {
+func (s *CardStore) Create(ctx context.Context, card *models.Card) error {
 	query := `
-		INSERT INTO cards (id, name, game, set_code, set_name, number, rarity, image_url, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO cards (id, name, game, set_code, set_name, number, rarity, image_url, created_at, updated_at, released_at, price_usd)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
-	_, err := s.db.Exec(query,
+	_, err := s.db.ExecContext(ctx, query,
 		card.ID,
 		card.Name,
-		card.Game,
+		gameKey(card.Game),
 		card.SetCode,
 		card.SetName,
 		card.Number,
 		card.Rarity,
-		card.ImageURL,
+		card.ImageUrl,
 		card.CreatedAt,
 		card.UpdatedAt,
+		card.ReleasedAt,
+		card.PriceUsd,
 	)
 	return err
 }
 
 // Update updates an existing card in the database
-func (s *CardStore) Update(card *types.Card) error {
+func (s *CardStore) Update(ctx context.Context, card *models.Card) error {
 	query := `
 		UPDATE cards
-		SET name = $1, game = $2, set_code = $3, set_name = $4, number = $5, rarity = $6, image_url = $7, updated_at = $8
-		WHERE id = $9
+		SET name = $1, game = $2, set_code = $3, set_name = $4, number = $5, rarity = $6, image_url = $7, updated_at = $8,
+			released_at = $9, price_usd = $10
+		WHERE id = $11
 	`
-	_, err := s.db.Exec(query,
+	_, err := s.db.ExecContext(ctx, query,
 		card.Name,
-		card.Game,
+		gameKey(card.Game),
 		card.SetCode,
 		card.SetName,
 		card.Number,
 		card.Rarity,
-		card.ImageURL,
+		card.ImageUrl,
 		card.UpdatedAt,
+		card.ReleasedAt,
+		card.PriceUsd,
 		card.ID,
 	)
 	return err
 }
 
-// FindByGameAndNumber finds a card by its game, set code, and number
-func (s *CardStore) FindByGameAndNumber(game, setCode, number string) (*types.Card, error) {
-	query := `
-		SELECT id, name, game, set_code, set_name, number, rarity, image_url, created_at, updated_at
-		FROM cards
-		WHERE LOWER(game) = LOWER($1) 
-		AND set_code = $2 
-		AND number = $3
-	`
-	row := s.db.QueryRow(query, game, setCode, number)
+// Delete removes a card from the database
+func (s *CardStore) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM cards WHERE id = $1`, id)
+	return err
+}
 
-	var card types.Card
-	err := row.Scan(
-		&card.ID,
-		&card.Name,
-		&card.Game,
-		&card.SetCode,
-		&card.SetName,
-		&card.Number,
-		&card.Rarity,
-		&card.ImageURL,
-		&card.CreatedAt,
-		&card.UpdatedAt,
-	)
+// FindByID returns a card by its ID, or nil if it does not exist
+func (s *CardStore) FindByID(ctx context.Context, id uuid.UUID) (*models.Card, error) {
+	query := `SELECT ` + cardColumns + ` FROM cards c WHERE c.id = $1`
+
+	card, err := scanCard(s.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
-	if err != nil {
-		return nil, err
-	}
-	return &card, nil
+	return card, err
 }
 
-// SearchCards searches for cards by name and game
-func (s *CardStore) SearchCards(query string, game string) ([]*types.Card, error) {
-	sqlQuery := `
-		SELECT id, name, game, set_code, set_name, number, rarity, image_url, created_at, updated_at
-		FROM cards
-		WHERE game = $1 AND name ILIKE $2
-		ORDER BY name
-		LIMIT 50
+// FindByGameAndNumber finds a card by its game, set code, and number, or returns nil if there
+// is no such card
+func (s *CardStore) FindByGameAndNumber(ctx context.Context, game, setCode, number string) (*models.Card, error) {
+	query := `
+		SELECT ` + cardColumns + `
+		FROM cards c
+		WHERE c.game = $1
+		AND c.set_code = $2
+		AND c.number = $3
 	`
-	rows, err := s.db.Query(sqlQuery, game, "%"+query+"%")
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
 
-	var cards []*types.Card
-	for rows.Next() {
-		var card types.Card
-		err := rows.Scan(
-			&card.ID,
-			&card.Name,
-			&card.Game,
-			&card.SetCode,
-			&card.SetName,
-			&card.Number,
-			&card.Rarity,
-			&card.ImageURL,
-			&card.CreatedAt,
-			&card.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		cards = append(cards, &card)
+	card, err := scanCard(s.db.QueryRowContext(ctx, query, gameKey(game), setCode, number))
+	if err == sql.ErrNoRows {
+		return nil, nil
 	}
-	return cards, nil
+	return card, err
 }
 
-func (s *CardStore) FindByID(id uuid.UUID) (*types.Card, error) {
+// FindBySet returns every card of a set, in collector number order. Numbers are compared by
+// their leading digits, so 2 comes before 10, and numbers without any, such as TG01, come last.
+func (s *CardStore) FindBySet(ctx context.Context, game, setCode string) ([]*models.Card, error) {
 	query := `
-		SELECT id, name, game, set_code, set_name, number, rarity, image_url, created_at, updated_at
-		FROM cards
-		WHERE id = $1
+		SELECT ` + cardColumns + `
+		FROM cards c
+		WHERE c.game = $1 AND c.set_code = $2
+		ORDER BY substring(c.number FROM '^[0-9]+')::numeric NULLS LAST, c.number, c.id
 	`
+	return s.queryCards(ctx, query, gameKey(game), setCode)
+}
 
-	card := &types.Card{}
-	err := s.db.QueryRow(query, id).Scan(
-		&card.ID,
-		&card.Name,
-		&card.Game,
-		&card.SetCode,
-		&card.SetName,
-		&card.Number,
-		&card.Rarity,
-		&card.ImageURL,
-		&card.CreatedAt,
-		&card.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, nil
+// FindByGame lists the cards of a game, by set and number unless another sort is given
+func (s *CardStore) FindByGame(ctx context.Context, game string, opts PageOptions) (*CardPage, error) {
+	if opts.SortBy == "" {
+		opts.SortBy = SortSet
 	}
+	sort, err := newCardSort(opts.SortBy, opts.SortOrder, "")
 	if err != nil {
 		return nil, err
 	}
-	return card, nil
+
+	from := ` FROM cards c WHERE c.game = $1`
+	return s.findPage(ctx, from, []interface{}{gameKey(game)}, sort, opts.First, opts.After, 0)
 }
 
-func (s *CardStore) FindByGame(game string, first int, after string) ([]*types.Card, string, error) {
-	query := `
-		SELECT id, name, game, set_code, set_name, number, rarity, image_url, created_at, updated_at
-		FROM cards
-		WHERE LOWER(game) = LOWER($1)
-		AND ($2 = '' OR (set_code > $2) OR (set_code = $2 AND number > $3))
-		ORDER BY set_code, number
-		LIMIT $4
-	`
+// SearchCards returns one page of the cards matching the search options, together with the
+// number of matching cards. The name is matched against the full text of each card, with
+// typo-tolerant trigram matching on the card name, and results are ranked by relevance unless
+// another sort is given. With an after cursor the page starts past that card; otherwise Page
+// and PageSize select it.
+func (s *CardStore) SearchCards(ctx context.Context, opts types.SearchOptions) (*CardPage, error) {
+	where := " WHERE c.game = $1"
+	args := []interface{}{gameKey(opts.Game)}
+	argCount := 2
 
-	// Parse the after cursor to get set_code and number
-	var setCode, number string
-	if after != "" {
-		parts := strings.Split(after, ":")
-		if len(parts) != 2 {
-			return nil, "", fmt.Errorf("invalid cursor format")
-		}
-		setCode, number = parts[0], parts[1]
+	if opts.SetCode != "" {
+		where += fmt.Sprintf(" AND LOWER(c.set_code) = LOWER($%d)", argCount)
+		args = append(args, opts.SetCode)
+		argCount++
 	}
 
-	rows, err := s.db.Query(query, game, setCode, number, first)
-	if err != nil {
-		return nil, "", err
+	if opts.Number != "" {
+		where += fmt.Sprintf(" AND LOWER(c.number) = LOWER($%d)", argCount)
+		args = append(args, opts.Number)
+		argCount++
 	}
-	defer rows.Close()
 
-	var cards []*types.Card
-	var lastSetCode, lastNumber string
-	for rows.Next() {
-		card := &types.Card{}
-		err := rows.Scan(
-			&card.ID,
-			&card.Name,
-			&card.Game,
-			&card.SetCode,
-			&card.SetName,
-			&card.Number,
-			&card.Rarity,
-			&card.ImageURL,
-			&card.CreatedAt,
-			&card.UpdatedAt,
-		)
-		if err != nil {
-			return nil, "", err
-		}
-		cards = append(cards, card)
-		lastSetCode = card.SetCode
-		lastNumber = card.Number
+	if opts.Rarity != "" {
+		where += fmt.Sprintf(" AND LOWER(c.rarity) = LOWER($%d)", argCount)
+		args = append(args, opts.Rarity)
+		argCount++
 	}
 
-	// Check if there are more results
-	var hasMore bool
-	if len(cards) > 0 {
-		checkQuery := `
-			SELECT EXISTS (
-				SELECT 1
-				FROM cards
-				WHERE LOWER(game) = LOWER($1)
-				AND (set_code > $2 OR (set_code = $2 AND number > $3))
-				LIMIT 1
-			)
-		`
-		err = s.db.QueryRow(checkQuery, game, lastSetCode, lastNumber).Scan(&hasMore)
-		if err != nil {
-			return nil, "", err
-		}
+	rank := ""
+	name := strings.TrimSpace(opts.Name)
+	if name != "" {
+		// word_similarity (<%) catches misspellings such as "charzard" for Charizard
+		where += fmt.Sprintf(`
+			AND (c.search_vector @@ websearch_to_tsquery('english', $%[1]d)
+				OR $%[1]d <%% c.name
+				OR c.name ILIKE '%%' || $%[1]d || '%%')`, argCount)
+		// An exact name outranks any partial match. The score is rounded so it reads back
+		// exactly from a cursor.
+		rank = fmt.Sprintf(`round((CASE WHEN LOWER(c.name) = LOWER($%[1]d) THEN 10 ELSE 0 END
+			+ ts_rank(c.search_vector, websearch_to_tsquery('english', $%[1]d))
+			+ word_similarity($%[1]d, c.name))::numeric, 6)`, argCount)
+		args = append(args, name)
+		argCount++
 	}
 
-	// Generate the next cursor
-	var nextCursor string
-	if hasMore && len(cards) > 0 {
-		nextCursor = fmt.Sprintf("%s:%s", lastSetCode, lastNumber)
+	sort, err := newCardSort(opts.SortBy, opts.SortOrder, rank)
+	if err != nil {
+		return nil, err
 	}
+	sort.term = name
 
-	return cards, nextCursor, nil
-}
+	var total int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM cards c"+where, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count cards: %w", err)
+	}
 
-func (s *CardStore) FindBySet(game, setCode string) ([]*types.Card, error) {
-	query := `
-		SELECT id, name, game, set_code, set_name, number, rarity, image_url, created_at, updated_at
-		FROM cards
-		WHERE game = $1 AND set_code = $2
-		ORDER BY number
-	`
+	page, err := s.findPage(ctx, " FROM cards c"+where, args, sort, opts.PageSize, opts.After, (opts.Page-1)*opts.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	page.TotalCount = total
+	return page, nil
+}
 
-	rows, err := s.db.Query(query, game, setCode)
+// FindMatching lists the cards matching a condition, by name unless another sort is given.
+// The condition is parameterized SQL over cards c, mtg_cards m and pokemon_cards p, with its
+// values in args.
+func (s *CardStore) FindMatching(ctx context.Context, condition string, args []interface{}, opts PageOptions) (*CardPage, error) {
+	sort, err := newCardSort(opts.SortBy, opts.SortOrder, "")
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var cards []*types.Card
-	for rows.Next() {
-		card := &types.Card{}
-		err := rows.Scan(
-			&card.ID,
-			&card.Name,
-			&card.Game,
-			&card.SetCode,
-			&card.SetName,
-			&card.Number,
-			&card.Rarity,
-			&card.ImageURL,
-			&card.CreatedAt,
-			&card.UpdatedAt,
-		)
+	from := fmt.Sprintf(`
+		FROM cards c
+		LEFT JOIN mtg_cards m ON m.card_id = c.id
+		LEFT JOIN pokemon_cards p ON p.card_id = c.id
+		WHERE (%s)`, condition)
+	return s.findPage(ctx, from, args, sort, opts.First, opts.After, 0)
+}
+
+// Filters returns the set codes and rarities found among the cards of a game
+func (s *CardStore) Filters(ctx context.Context, game string) (*types.CardFilters, error) {
+	filters := &types.CardFilters{Sets: []string{}, Rarities: []string{}}
+
+	queries := []struct {
+		column string
+		values *[]string
+	}{
+		{"set_code", &filters.Sets},
+		{"rarity", &filters.Rarities},
+	}
+	for _, q := range queries {
+		query := fmt.Sprintf(`
+			SELECT DISTINCT %[1]s
+			FROM cards
+			WHERE game = $1 AND %[1]s <> ''
+			ORDER BY %[1]s
+		`, q.column)
+
+		rows, err := s.db.QueryContext(ctx, query, gameKey(game))
 		if err != nil {
+			return nil, fmt.Errorf("failed to load card filters: %w", err)
+		}
+		for rows.Next() {
+			var value string
+			if err := rows.Scan(&value); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			*q.values = append(*q.values, value)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
 			return nil, err
 		}
-		cards = append(cards, card)
 	}
-	return cards, nil
-}
 
-func (s *CardStore) Delete(id uuid.UUID) error {
-	query := `DELETE FROM cards WHERE id = $1`
-	_, err := s.db.Exec(query, id)
-	return err
-}
-
-// ToModel converts a types.Card to a models.Card
-func (s *CardStore) ToModel(card *types.Card) *models.Card {
-	return &models.Card{
-		ID:        card.ID,
-		Name:      card.Name,
-		Game:      card.Game,
-		SetCode:   card.SetCode,
-		SetName:   card.SetName,
-		Number:    card.Number,
-		Rarity:    card.Rarity,
-		ImageUrl:  card.ImageURL,
-		CreatedAt: card.CreatedAt,
-		UpdatedAt: card.UpdatedAt,
-	}
+	return filters, nil
 }
 
-// CreateBatch inserts multiple cards into the database
-func (s *CardStore) CreateBatch(tx *sql.Tx, cards []*types.Card) error {
+// CreateBatch inserts multiple cards within a transaction
+func (s *CardStore) CreateBatch(ctx context.Context, tx *sql.Tx, cards []*models.Card) error {
 	query := `
-		INSERT INTO cards (id, name, game, set_code, set_name, number, rarity, image_url, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO cards (id, name, game, set_code, set_name, number, rarity, image_url, created_at, updated_at, released_at, price_usd)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
-	stmt, err := tx.Prepare(query)
+	stmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
 	}
 	defer stmt.Close()
 
 	for _, card := range cards {
-		_, err := stmt.Exec(
+		_, err := stmt.ExecContext(ctx,
 			card.ID,
 			card.Name,
-			card.Game,
+			gameKey(card.Game),
 			card.SetCode,
 			card.SetName,
 			card.Number,
 			card.Rarity,
-			card.ImageURL,
+			card.ImageUrl,
 			card.CreatedAt,
 			card.UpdatedAt,
+			card.ReleasedAt,
+			card.PriceUsd,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to create card: %w", err)
@@ -330,29 +350,32 @@ func (s *CardStore) CreateBatch(tx *sql.Tx, cards []*types.Card) error {
 	return nil
 }
 
-// UpdateBatch updates multiple cards in the database
-func (s *CardStore) UpdateBatch(tx *sql.Tx, cards []*types.Card) error {
+// UpdateBatch updates multiple cards within a transaction
+func (s *CardStore) UpdateBatch(ctx context.Context, tx *sql.Tx, cards []*models.Card) error {
 	query := `
 		UPDATE cards
-		SET name = $1, game = $2, set_code = $3, set_name = $4, number = $5, rarity = $6, image_url = $7, updated_at = $8
-		WHERE id = $9
+		SET name = $1, game = $2, set_code = $3, set_name = $4, number = $5, rarity = $6, image_url = $7, updated_at = $8,
+			released_at = $9, price_usd = $10
+		WHERE id = $11
 	`
-	stmt, err := tx.Prepare(query)
+	stmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
 	}
 	defer stmt.Close()
 
 	for _, card := range cards {
-		_, err := stmt.Exec(
+		_, err := stmt.ExecContext(ctx,
 			card.Name,
-			card.Game,
+			gameKey(card.Game),
 			card.SetCode,
 			card.SetName,
 			card.Number,
 			card.Rarity,
-			card.ImageURL,
+			card.ImageUrl,
 			card.UpdatedAt,
+			card.ReleasedAt,
+			card.PriceUsd,
 			card.ID,
 		)
 		if err != nil {
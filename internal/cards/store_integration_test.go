@@ -0,0 +1,466 @@
+package cards
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/shiftregister-vg/card-craft/internal/database"
+	"github.com/shiftregister-vg/card-craft/internal/models"
+	"github.com/shiftregister-vg/card-craft/internal/types"
+)
+
+// testDatabaseEnv names the Postgres database the integration tests run against, as a URL or
+// a key=value connection string. The tests are skipped when it is not set.
+const testDatabaseEnv = "CARD_CRAFT_TEST_DATABASE_URL"
+
+// openTestDB migrates a schema of its own in the test database, so tests neither see nor
+// leave behind any other data, and drops it when the test ends
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv(testDatabaseEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDatabaseEnv)
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	suffix := make([]byte, 6)
+	rand.Read(suffix)
+	schema := "card_craft_test_" + hex.EncodeToString(suffix)
+	if _, err := admin.Exec("CREATE SCHEMA " + pq.QuoteIdentifier(schema)); err != nil {
+		t.Fatalf("failed to create test schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec("DROP SCHEMA " + pq.QuoteIdentifier(schema) + " CASCADE"); err != nil {
+			t.Errorf("failed to drop test schema %s: %v", schema, err)
+		}
+	})
+
+	// Extensions already installed in public stay visible
+	searchPath := schema + ",public"
+	if strings.Contains(dsn, "://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			t.Fatalf("invalid %s: %v", testDatabaseEnv, err)
+		}
+		q := u.Query()
+		q.Set("search_path", searchPath)
+		u.RawQuery = q.Encode()
+		dsn = u.String()
+	} else {
+		dsn += " search_path=" + searchPath
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to open test schema: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := (&database.Database{DB: db}).Migrate("../../migrations"); err != nil {
+		t.Fatalf("failed to migrate test schema: %v", err)
+	}
+	return db
+}
+
+// newTestCard builds a card that has not been stored yet
+func newTestCard(game, setCode, number, name, rarity string) *models.Card {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	return &models.Card{
+		ID:        uuid.New(),
+		Name:      name,
+		Game:      game,
+		SetCode:   setCode,
+		SetName:   strings.ToUpper(setCode),
+		Number:    number,
+		Rarity:    rarity,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// createTestCards stores cards, failing the test on the first error
+func createTestCards(t *testing.T, store *CardStore, cards ...*models.Card) {
+	t.Helper()
+	for _, card := range cards {
+		if err := store.Create(context.Background(), card); err != nil {
+			t.Fatalf("failed to create %s: %v", card.Name, err)
+		}
+	}
+}
+
+// cardNames returns the names of cards, in order
+func cardNames(cards []*models.Card) []string {
+	names := make([]string, len(cards))
+	for i, card := range cards {
+		names[i] = card.Name
+	}
+	return names
+}
+
+func TestCardStoreCRUD(t *testing.T) {
+	ctx := context.Background()
+	store := NewCardStore(openTestDB(t))
+
+	card := newTestCard("MTG", "lea", "161", "Lightning Bolt", "common")
+	card.ImageUrl = "https://example.com/bolt.jpg"
+	released := time.Date(1993, 8, 5, 0, 0, 0, 0, time.UTC)
+	price := 1.5
+	card.ReleasedAt = &released
+	card.PriceUsd = &price
+	createTestCards(t, store, card)
+
+	got, err := store.FindByID(ctx, card.ID)
+	if err != nil || got == nil {
+		t.Fatalf("FindByID() = %v, %v", got, err)
+	}
+	if got.Game != "mtg" {
+		t.Errorf("game stored as %q, want it in lower case", got.Game)
+	}
+	if got.Name != card.Name || got.SetCode != "lea" || got.Number != "161" || got.Rarity != "common" || got.ImageUrl != card.ImageUrl {
+		t.Errorf("FindByID() = %+v, want the created card", got)
+	}
+	if got.ReleasedAt == nil || !got.ReleasedAt.Equal(released) {
+		t.Errorf("ReleasedAt = %v, want %v", got.ReleasedAt, released)
+	}
+	if got.PriceUsd == nil || *got.PriceUsd != price {
+		t.Errorf("PriceUsd = %v, want %v", got.PriceUsd, price)
+	}
+
+	found, err := store.FindByGameAndNumber(ctx, "Mtg", "lea", "161")
+	if err != nil || found == nil || found.ID != card.ID {
+		t.Errorf("FindByGameAndNumber() = %v, %v, want the created card", found, err)
+	}
+	if missing, err := store.FindByGameAndNumber(ctx, "mtg", "lea", "162"); err != nil || missing != nil {
+		t.Errorf("FindByGameAndNumber() of a missing card = %v, %v, want nil", missing, err)
+	}
+
+	card.Name = "Lightning Bolt (Alpha)"
+	card.PriceUsd = nil
+	card.UpdatedAt = time.Now().UTC()
+	if err := store.Update(ctx, card); err != nil {
+		t.Fatalf("Update(): %v", err)
+	}
+	got, err = store.FindByID(ctx, card.ID)
+	if err != nil || got == nil {
+		t.Fatalf("FindByID() after Update = %v, %v", got, err)
+	}
+	if got.Name != "Lightning Bolt (Alpha)" || got.PriceUsd != nil {
+		t.Errorf("after Update, name = %q and price = %v", got.Name, got.PriceUsd)
+	}
+
+	if err := store.Delete(ctx, card.ID); err != nil {
+		t.Fatalf("Delete(): %v", err)
+	}
+	if got, err := store.FindByID(ctx, card.ID); err != nil || got != nil {
+		t.Errorf("FindByID() after Delete = %v, %v, want nil", got, err)
+	}
+}
+
+func TestCardStoreBatches(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	store := NewCardStore(db)
+
+	cards := []*models.Card{
+		newTestCard("pokemon", "base1", "58", "Pikachu", "Common"),
+		newTestCard("pokemon", "base1", "14", "Raichu", "Rare Holo"),
+		newTestCard("pokemon", "base1", "4", "Charizard", "Rare Holo"),
+	}
+
+	// A rolled back batch leaves nothing behind
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CreateBatch(ctx, tx, cards); err != nil {
+		t.Fatalf("CreateBatch(): %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.FindBySet(ctx, "pokemon", "base1"); err != nil || len(got) != 0 {
+		t.Fatalf("FindBySet() after a rollback = %v, %v, want no cards", cardNames(got), err)
+	}
+
+	tx, err = db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CreateBatch(ctx, tx, cards); err != nil {
+		t.Fatalf("CreateBatch(): %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, card := range cards {
+		card.Rarity = "Promo"
+	}
+	tx, err = db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.UpdateBatch(ctx, tx, cards[:2]); err != nil {
+		t.Fatalf("UpdateBatch(): %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"Pikachu": "Promo", "Raichu": "Promo", "Charizard": "Rare Holo"}
+	got, err := store.FindBySet(ctx, "pokemon", "base1")
+	if err != nil {
+		t.Fatalf("FindBySet(): %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("FindBySet() = %v, want %d cards", cardNames(got), len(want))
+	}
+	for _, card := range got {
+		if card.Rarity != want[card.Name] {
+			t.Errorf("%s has rarity %q, want %q", card.Name, card.Rarity, want[card.Name])
+		}
+	}
+}
+
+func TestCardStoreFindBySetOrder(t *testing.T) {
+	store := NewCardStore(openTestDB(t))
+	for _, number := range []string{"10", "TG01", "2", "10a", "1"} {
+		createTestCards(t, store, newTestCard("pokemon", "swsh9", number, "Card "+number, "Common"))
+	}
+	createTestCards(t, store, newTestCard("pokemon", "swsh10", "3", "Card from another set", "Common"))
+
+	got, err := store.FindBySet(context.Background(), "Pokemon", "swsh9")
+	if err != nil {
+		t.Fatalf("FindBySet(): %v", err)
+	}
+	want := []string{"Card 1", "Card 2", "Card 10", "Card 10a", "Card TG01"}
+	if names := cardNames(got); !reflect.DeepEqual(names, want) {
+		t.Errorf("FindBySet() = %v, want %v", names, want)
+	}
+}
+
+// seedSearchCards stores a few cards of two games to search through
+func seedSearchCards(t *testing.T, store *CardStore) {
+	t.Helper()
+	bolt := newTestCard("mtg", "lea", "161", "Lightning Bolt", "common")
+	helix := newTestCard("mtg", "rav", "213", "Lightning Helix", "uncommon")
+	counterspell := newTestCard("mtg", "lea", "54", "Counterspell", "uncommon")
+	pikachu := newTestCard("pokemon", "base1", "58", "Pikachu", "Common")
+	raichu := newTestCard("pokemon", "base1", "14", "Raichu", "Rare")
+
+	boltPrice, helixPrice := 2.0, 0.5
+	bolt.PriceUsd = &boltPrice
+	helix.PriceUsd = &helixPrice
+
+	createTestCards(t, store, bolt, helix, counterspell, pikachu, raichu)
+}
+
+func TestCardStoreSearchCards(t *testing.T) {
+	ctx := context.Background()
+	store := NewCardStore(openTestDB(t))
+	seedSearchCards(t, store)
+
+	tests := []struct {
+		name string
+		opts types.SearchOptions
+		// want lists the expected cards in order; unordered lists them by name
+		want      []string
+		unordered bool
+	}{
+		{
+			name: "game",
+			opts: types.SearchOptions{Game: "mtg", SortBy: SortName},
+			want: []string{"Counterspell", "Lightning Bolt", "Lightning Helix"},
+		},
+		{
+			name: "game in another case",
+			opts: types.SearchOptions{Game: "MTG", SortBy: SortName},
+			want: []string{"Counterspell", "Lightning Bolt", "Lightning Helix"},
+		},
+		{
+			name: "set",
+			opts: types.SearchOptions{Game: "mtg", SetCode: "LEA", SortBy: SortName},
+			want: []string{"Counterspell", "Lightning Bolt"},
+		},
+		{
+			name: "rarity",
+			opts: types.SearchOptions{Game: "mtg", Rarity: "Uncommon", SortBy: SortName},
+			want: []string{"Counterspell", "Lightning Helix"},
+		},
+		{
+			name: "number",
+			opts: types.SearchOptions{Game: "mtg", Number: "161"},
+			want: []string{"Lightning Bolt"},
+		},
+		{
+			name:      "name",
+			opts:      types.SearchOptions{Game: "mtg", Name: "lightning"},
+			want:      []string{"Lightning Bolt", "Lightning Helix"},
+			unordered: true,
+		},
+		{
+			name: "name in another game",
+			opts: types.SearchOptions{Game: "pokemon", Name: "pikachu"},
+			want: []string{"Pikachu"},
+		},
+		{
+			name: "price, highest first with unpriced cards last",
+			opts: types.SearchOptions{Game: "mtg", SortBy: SortPrice, SortOrder: SortDesc},
+			want: []string{"Lightning Bolt", "Lightning Helix", "Counterspell"},
+		},
+		{
+			name: "no matches",
+			opts: types.SearchOptions{Game: "lorcana"},
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.Page, opts.PageSize = 1, 10
+			page, err := store.SearchCards(ctx, opts)
+			if err != nil {
+				t.Fatalf("SearchCards(): %v", err)
+			}
+			names := cardNames(page.Cards)
+			if tt.unordered {
+				names = cardNames(sortedByName(page.Cards))
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("SearchCards() = %v, want %v", names, tt.want)
+			}
+			if page.TotalCount != len(tt.want) {
+				t.Errorf("TotalCount = %d, want %d", page.TotalCount, len(tt.want))
+			}
+			if page.HasNextPage {
+				t.Errorf("HasNextPage is set on the only page")
+			}
+		})
+	}
+
+	// The exact name outranks the partial matches
+	page, err := store.SearchCards(ctx, types.SearchOptions{Game: "mtg", Name: "Lightning Helix", Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("SearchCards(): %v", err)
+	}
+	if len(page.Cards) == 0 || page.Cards[0].Name != "Lightning Helix" {
+		t.Errorf("SearchCards() by exact name = %v, want Lightning Helix first", cardNames(page.Cards))
+	}
+}
+
+func TestCardStoreSearchCardsPaging(t *testing.T) {
+	ctx := context.Background()
+	store := NewCardStore(openTestDB(t))
+	seedSearchCards(t, store)
+
+	opts := types.SearchOptions{Game: "mtg", SortBy: SortName, Page: 1, PageSize: 2}
+	first, err := store.SearchCards(ctx, opts)
+	if err != nil {
+		t.Fatalf("SearchCards(): %v", err)
+	}
+	if names := cardNames(first.Cards); !reflect.DeepEqual(names, []string{"Counterspell", "Lightning Bolt"}) || !first.HasNextPage {
+		t.Fatalf("first page = %v (next page %v), want Counterspell and Lightning Bolt with another page", names, first.HasNextPage)
+	}
+	if first.TotalCount != 3 {
+		t.Errorf("TotalCount = %d, want 3", first.TotalCount)
+	}
+
+	opts.After = first.Cursors[len(first.Cursors)-1]
+	second, err := store.SearchCards(ctx, opts)
+	if err != nil {
+		t.Fatalf("SearchCards() after a cursor: %v", err)
+	}
+	if names := cardNames(second.Cards); !reflect.DeepEqual(names, []string{"Lightning Helix"}) || second.HasNextPage {
+		t.Errorf("second page = %v (next page %v), want only Lightning Helix", names, second.HasNextPage)
+	}
+	if second.TotalCount != 3 {
+		t.Errorf("TotalCount after a cursor = %d, want 3", second.TotalCount)
+	}
+
+	// Page numbers select the same pages
+	opts.After, opts.Page = "", 2
+	byNumber, err := store.SearchCards(ctx, opts)
+	if err != nil {
+		t.Fatalf("SearchCards() of page 2: %v", err)
+	}
+	if names := cardNames(byNumber.Cards); !reflect.DeepEqual(names, []string{"Lightning Helix"}) {
+		t.Errorf("page 2 = %v, want only Lightning Helix", names)
+	}
+
+	// A cursor cannot continue a search under another sort
+	opts.Page, opts.After, opts.SortBy = 1, first.Cursors[0], SortSet
+	if _, err := store.SearchCards(ctx, opts); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("SearchCards() with a cursor of another sort returned %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestCardStoreFindByGame(t *testing.T) {
+	ctx := context.Background()
+	store := NewCardStore(openTestDB(t))
+	seedSearchCards(t, store)
+
+	// By set and then number, compared as text
+	first, err := store.FindByGame(ctx, "mtg", PageOptions{First: 2})
+	if err != nil {
+		t.Fatalf("FindByGame(): %v", err)
+	}
+	if names := cardNames(first.Cards); !reflect.DeepEqual(names, []string{"Lightning Bolt", "Counterspell"}) || !first.HasNextPage {
+		t.Fatalf("first page = %v (next page %v), want Lightning Bolt and Counterspell with another page", names, first.HasNextPage)
+	}
+
+	second, err := store.FindByGame(ctx, "MTG", PageOptions{First: 2, After: first.Cursors[1]})
+	if err != nil {
+		t.Fatalf("FindByGame() after a cursor: %v", err)
+	}
+	if names := cardNames(second.Cards); !reflect.DeepEqual(names, []string{"Lightning Helix"}) || second.HasNextPage {
+		t.Errorf("second page = %v (next page %v), want only Lightning Helix", names, second.HasNextPage)
+	}
+}
+
+func TestCardStoreFilters(t *testing.T) {
+	ctx := context.Background()
+	store := NewCardStore(openTestDB(t))
+	seedSearchCards(t, store)
+
+	filters, err := store.Filters(ctx, "MTG")
+	if err != nil {
+		t.Fatalf("Filters(): %v", err)
+	}
+	want := &types.CardFilters{Sets: []string{"lea", "rav"}, Rarities: []string{"common", "uncommon"}}
+	if !reflect.DeepEqual(filters, want) {
+		t.Errorf("Filters() = %+v, want %+v", filters, want)
+	}
+
+	empty, err := store.Filters(ctx, "lorcana")
+	if err != nil {
+		t.Fatalf("Filters(): %v", err)
+	}
+	if len(empty.Sets) != 0 || len(empty.Rarities) != 0 || empty.Sets == nil || empty.Rarities == nil {
+		t.Errorf("Filters() of a game without cards = %+v, want empty lists", empty)
+	}
+}
+
+// sortedByName returns cards ordered by name
+func sortedByName(cards []*models.Card) []*models.Card {
+	sorted := append([]*models.Card(nil), cards...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted
+}
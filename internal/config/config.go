@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -24,6 +25,12 @@ type Config struct {
 	Port              string
 	Environment       string
 	EnableCardImports bool
+	// ImportSchedule is the cron expression scheduled imports run on by default
+	ImportSchedule string
+	// ImportSchedules overrides ImportSchedule per game, from IMPORT_SCHEDULE_<GAME> variables
+	ImportSchedules  map[string]string
+	ImportJitter     time.Duration
+	ImportMaxRuntime time.Duration
 }
 
 func Load() (*Config, error) {
@@ -50,6 +57,8 @@ func Load() (*Config, error) {
 	jwtExpiration, _ := time.ParseDuration(getEnv("JWT_EXPIRATION", "24h"))
 	rateLimitPeriod, _ := time.ParseDuration(getEnv("RATE_LIMIT_PERIOD", "1m"))
 	enableCardImports, _ := strconv.ParseBool(getEnv("ENABLE_CARD_IMPORTS", "false"))
+	importJitter, _ := time.ParseDuration(getEnv("IMPORT_JITTER", "10m"))
+	importMaxRuntime, _ := time.ParseDuration(getEnv("IMPORT_MAX_RUNTIME", "6h"))
 
 	return &Config{
 		DBHost:            getEnv("DB_HOST", "localhost"),
@@ -65,9 +74,27 @@ func Load() (*Config, error) {
 		Port:              getEnv("PORT", "8080"),
 		Environment:       getEnv("ENVIRONMENT", "development"),
 		EnableCardImports: enableCardImports,
+		ImportSchedule:    getEnv("IMPORT_SCHEDULE", "0 3 * * *"),
+		ImportSchedules:   importSchedules(),
+		ImportJitter:      importJitter,
+		ImportMaxRuntime:  importMaxRuntime,
 	}, nil
 }
 
+// importSchedules collects the per-game import schedules, such as IMPORT_SCHEDULE_MTG="0 4 * * *"
+func importSchedules() map[string]string {
+	const prefix = "IMPORT_SCHEDULE_"
+
+	schedules := make(map[string]string)
+	for _, variable := range os.Environ() {
+		key, value, _ := strings.Cut(variable, "=")
+		if game, ok := strings.CutPrefix(key, prefix); ok && game != "" {
+			schedules[strings.ToLower(game)] = value
+		}
+	}
+	return schedules
+}
+
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+)
+
+// AdvisoryLock is a Postgres session-level advisory lock held on a dedicated connection
+type AdvisoryLock struct {
+	conn *sql.Conn
+	key  int64
+}
+
+// TryAdvisoryLock attempts to take the advisory lock identified by name without waiting.
+// It returns nil if another session holds the lock. Every process that locks the same name
+// contends for the same lock, so it can keep replicas from doing the same work at once.
+func TryAdvisoryLock(ctx context.Context, db *sql.DB, name string) (*AdvisoryLock, error) {
+	hash := fnv.New64a()
+	hash.Write([]byte(name))
+	key := int64(hash.Sum64())
+
+	// Advisory locks belong to a session, so the lock and unlock must use the same connection
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection for advisory lock: %w", err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to take advisory lock %s: %w", name, err)
+	}
+	if !acquired {
+		conn.Close()
+		return nil, nil
+	}
+
+	return &AdvisoryLock{conn: conn, key: key}, nil
+}
+
+// Release releases the lock and returns its connection to the pool
+func (l *AdvisoryLock) Release() error {
+	defer l.conn.Close()
+
+	// The caller's context may already be done, but the lock must still be released
+	if _, err := l.conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", l.key); err != nil {
+		return fmt.Errorf("failed to release advisory lock: %w", err)
+	}
+	return nil
+}
@@ -17,14 +17,15 @@ type Migration struct {
 	Down    string
 }
 
+// Migrate applies the up migrations in migrationsDir that have not been applied yet, in order
 func (db *Database) Migrate(migrationsDir string) error {
 	// Create migrations table if it doesn't exist
 	if err := db.createMigrationsTable(); err != nil {
 		return fmt.Errorf("error creating migrations table: %w", err)
 	}
 
-	// Get all migration files
-	files, err := filepath.Glob(filepath.Join(migrationsDir, "*.sql"))
+	// Get all migration files, leaving out the down migrations stored next to them
+	files, err := filepath.Glob(filepath.Join(migrationsDir, "*.up.sql"))
 	if err != nil {
 		return fmt.Errorf("error reading migration files: %w", err)
 	}
@@ -40,7 +41,7 @@ func (db *Database) Migrate(migrationsDir string) error {
 
 	// Apply pending migrations
 	for _, file := range files {
-		version := strings.TrimSuffix(filepath.Base(file), ".sql")
+		version := strings.TrimSuffix(filepath.Base(file), ".up.sql")
 		if _, ok := applied[version]; !ok {
 			content, err := fs.ReadFile(os.DirFS(migrationsDir), filepath.Base(file))
 			if err != nil {
@@ -30,6 +30,11 @@ func (t *Transaction) Rollback() error {
 	return t.tx.Rollback()
 }
 
+// Tx returns the underlying sql.Tx, for stores whose batch operations take one
+func (t *Transaction) Tx() *sql.Tx {
+	return t.tx
+}
+
 // Exec executes a query within the transaction
 func (t *Transaction) Exec(query string, args ...interface{}) (sql.Result, error) {
 	return t.tx.Exec(query, args...)
@@ -0,0 +1,191 @@
+package decklist
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shiftregister-vg/card-craft/internal/models"
+)
+
+var (
+	// ErrUnsupportedGame is returned for games without a deck list format
+	ErrUnsupportedGame = errors.New("deck lists are not supported for this game")
+	// ErrUnsupportedFormat is returned when a game has no deck list format of the requested name
+	ErrUnsupportedFormat = errors.New("unsupported deck list format")
+	// ErrInvalidList is returned when a deck list cannot be read at all, such as malformed XML
+	ErrInvalidList = errors.New("invalid deck list")
+	// ErrNoCards is returned when no line of a deck list could be matched to a card
+	ErrNoCards = errors.New("no cards in the deck list could be found")
+)
+
+// Line is a card line parsed from a deck list
+type Line struct {
+	// Number is the line's position in the text, starting at 1
+	Number   int
+	Text     string
+	Quantity int
+	Name     string
+	// SetCode and CardNumber identify the printing, when the list names one
+	SetCode    string
+	CardNumber string
+	// Zone is the part of the deck the line belongs to, such as models.ZoneSideboard
+	Zone string
+}
+
+// Problem describes a line of a deck list that could not be imported
+type Problem struct {
+	Line   int    `json:"line"`
+	Text   string `json:"text"`
+	Reason string `json:"reason"`
+}
+
+// ImportResult is the outcome of importing a deck list
+type ImportResult struct {
+	Deck      *models.Deck `json:"deck"`
+	CardCount int          `json:"cardCount"`
+	// Unresolved lists the lines that were skipped because they could not be parsed or matched
+	Unresolved []*Problem `json:"unresolved"`
+}
+
+// Service imports and exports deck lists
+type Service struct {
+	db        *sql.DB
+	deckStore *models.DeckStore
+}
+
+// NewService creates a new deck list service
+func NewService(db *sql.DB, deckStore *models.DeckStore) *Service {
+	return &Service{db: db, deckStore: deckStore}
+}
+
+// Import parses a deck list, matches its lines to cards and creates the deck with them.
+// The deck must have its owner, name and game set. Lines that cannot be matched are skipped
+// and reported in the result; if none can be matched, ErrNoCards is returned.
+func (s *Service) Import(ctx context.Context, deck *models.Deck, format, text string) (*ImportResult, error) {
+	var lines []*Line
+	var problems []*Problem
+	var resolve func(ctx context.Context, line *Line) (uuid.UUID, error)
+
+	switch strings.ToLower(deck.Game) {
+	case "pokemon":
+		if err := checkFormat(deck.Game, format, pokemonFormats); err != nil {
+			return nil, err
+		}
+		lines, problems = parsePTCGL(text)
+		resolve = s.resolvePokemonCard
+	case "mtg":
+		if err := checkFormat(deck.Game, format, mtgFormats); err != nil {
+			return nil, err
+		}
+		var err error
+		if lines, problems, err = parseMTG(text); err != nil {
+			return nil, err
+		}
+		resolve = s.resolveMTGCard
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedGame, deck.Game)
+	}
+
+	// Merge lines naming the same card, since a deck holds each card once per zone
+	type deckCardKey struct {
+		cardID uuid.UUID
+		zone   string
+	}
+	var deckCards []*models.DeckCard
+	byKey := make(map[deckCardKey]*models.DeckCard)
+	cardCount := 0
+	for _, line := range lines {
+		cardID, err := resolve(ctx, line)
+		if err != nil {
+			return nil, err
+		}
+		if cardID == uuid.Nil {
+			problems = append(problems, &Problem{
+				Line:   line.Number,
+				Text:   line.Text,
+				Reason: "no matching card was found",
+			})
+			continue
+		}
+
+		zone := line.Zone
+		if zone == "" {
+			zone = models.ZoneMain
+		}
+
+		key := deckCardKey{cardID: cardID, zone: zone}
+		if deckCard, ok := byKey[key]; ok {
+			deckCard.Quantity += line.Quantity
+		} else {
+			deckCard := &models.DeckCard{CardID: cardID, Quantity: line.Quantity, Zone: zone}
+			byKey[key] = deckCard
+			deckCards = append(deckCards, deckCard)
+		}
+		cardCount += line.Quantity
+	}
+
+	if len(deckCards) == 0 {
+		return nil, ErrNoCards
+	}
+
+	if deck.ID == uuid.Nil {
+		deck.ID = uuid.New()
+	}
+	if err := s.deckStore.CreateWithCards(ctx, deck, deckCards, "Imported from a deck list"); err != nil {
+		return nil, fmt.Errorf("failed to create deck: %w", err)
+	}
+
+	if problems == nil {
+		problems = []*Problem{}
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+
+	return &ImportResult{
+		Deck:       deck,
+		CardCount:  cardCount,
+		Unresolved: problems,
+	}, nil
+}
+
+// Export renders the cards of a deck as a deck list. An empty format selects the game's default.
+func (s *Service) Export(ctx context.Context, deck *models.Deck, format string) (string, error) {
+	switch strings.ToLower(deck.Game) {
+	case "pokemon":
+		if err := checkFormat(deck.Game, format, pokemonFormats); err != nil {
+			return "", err
+		}
+		cards, err := s.loadPokemonCards(ctx, deck.ID)
+		if err != nil {
+			return "", err
+		}
+		return renderPTCGL(cards), nil
+	case "mtg":
+		if err := checkFormat(deck.Game, format, mtgFormats); err != nil {
+			return "", err
+		}
+		cards, err := s.loadMTGCards(ctx, deck.ID)
+		if err != nil {
+			return "", err
+		}
+		return renderMTG(cards, strings.ToLower(strings.TrimSpace(format)))
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedGame, deck.Game)
+	}
+}
+
+// checkFormat verifies that a game supports the requested deck list format. An empty format is
+// always accepted and means the game's default.
+func checkFormat(game, format string, formats map[string]bool) error {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" || formats[format] {
+		return nil
+	}
+	return fmt.Errorf("%w: %s has no %s format", ErrUnsupportedFormat, game, format)
+}
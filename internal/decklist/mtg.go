@@ -0,0 +1,395 @@
+package decklist
+
+import (
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shiftregister-vg/card-craft/internal/models"
+)
+
+// MTG deck list formats
+const (
+	// mtgFormatArena is MTG Arena's export text, naming each printing by set and collector number
+	mtgFormatArena = "arena"
+	// mtgFormatMTGO is the XML .dek file of Magic: The Gathering Online
+	mtgFormatMTGO = "mtgo"
+	// mtgFormatText is a plain "4 Lightning Bolt" list
+	mtgFormatText = "text"
+)
+
+// mtgFormats lists the MTG deck list formats. Imports detect the format from the text,
+// so the format only matters for exports.
+var mtgFormats = map[string]bool{
+	mtgFormatArena: true,
+	mtgFormatMTGO:  true,
+	mtgFormatText:  true,
+}
+
+// mtgZoneHeaders maps the section headers of MTG deck lists to deck zones
+var mtgZoneHeaders = map[string]string{
+	"deck":        models.ZoneMain,
+	"main":        models.ZoneMain,
+	"maindeck":    models.ZoneMain,
+	"main deck":   models.ZoneMain,
+	"mainboard":   models.ZoneMain,
+	"sideboard":   models.ZoneSideboard,
+	"side":        models.ZoneSideboard,
+	"commander":   models.ZoneCommander,
+	"commanders":  models.ZoneCommander,
+	"companion":   models.ZoneCompanion,
+	"maybeboard":  models.ZoneMaybeboard,
+	"maybe":       models.ZoneMaybeboard,
+	"considering": models.ZoneMaybeboard,
+}
+
+// mtgZoneSections lists the zones in the order lists present them, with their headers
+var mtgZoneSections = []struct {
+	zone   string
+	header string
+}{
+	{models.ZoneCommander, "Commander"},
+	{models.ZoneCompanion, "Companion"},
+	{models.ZoneMain, "Deck"},
+	{models.ZoneSideboard, "Sideboard"},
+	{models.ZoneMaybeboard, "Maybeboard"},
+}
+
+// arenaSetCodes maps the few set codes MTG Arena writes differently from Scryfall
+var arenaSetCodes = map[string]string{
+	"DAR":  "dom",
+	"CONF": "con",
+}
+
+var (
+	// mtgCard matches "4 Lightning Bolt", Arena's "4 Lightning Bolt (M10) 146" and "SB: 2 Duress"
+	mtgCard = regexp.MustCompile(`^(SB:\s*)?(\d+)x?\s+(.+?)(?:\s+\(([A-Za-z0-9]+)\)(?:\s+(\S+))?)?(?:\s+\*[A-Z]\*)?$`)
+	// mtgHeaderCount matches the card count some lists add to section headers, as in "Sideboard (15)"
+	mtgHeaderCount = regexp.MustCompile(`\s*:?\s*\(?\d*\)?$`)
+)
+
+// parseMTG parses an MTG deck list, detecting MTGO .dek XML and otherwise reading Arena or plain text
+func parseMTG(text string) ([]*Line, []*Problem, error) {
+	if strings.HasPrefix(strings.TrimSpace(text), "<") {
+		return parseMTGO(text)
+	}
+	lines, problems := parseMTGText(text)
+	return lines, problems, nil
+}
+
+// parseMTGText parses Arena export text or a plain list. Cards before any header belong to the
+// main deck, and in lists without headers a blank line starts the sideboard.
+func parseMTGText(text string) ([]*Line, []*Problem) {
+	var lines []*Line
+	var problems []*Problem
+
+	zone := models.ZoneMain
+	sawHeader := false
+	inAbout := false
+
+	for index, raw := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" {
+			inAbout = false
+			if !sawHeader && len(lines) > 0 {
+				zone = models.ZoneSideboard
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		// Arena exports start with an About section naming the deck
+		header := strings.ToLower(mtgHeaderCount.ReplaceAllString(trimmed, ""))
+		if header == "about" {
+			inAbout = true
+			continue
+		}
+		if inAbout {
+			continue
+		}
+		if headerZone, ok := mtgZoneHeaders[header]; ok {
+			zone = headerZone
+			sawHeader = true
+			continue
+		}
+
+		match := mtgCard.FindStringSubmatch(trimmed)
+		if match == nil {
+			problems = append(problems, &Problem{Line: index + 1, Text: trimmed, Reason: "expected a quantity followed by a card"})
+			continue
+		}
+
+		line := &Line{
+			Number:     index + 1,
+			Text:       trimmed,
+			Name:       match[3],
+			SetCode:    match[4],
+			CardNumber: match[5],
+			Zone:       zone,
+		}
+		line.Quantity, _ = strconv.Atoi(match[2])
+		if match[1] != "" {
+			line.Zone = models.ZoneSideboard
+		}
+
+		if line.Quantity < 1 {
+			problems = append(problems, &Problem{Line: line.Number, Text: trimmed, Reason: "quantity must be at least 1"})
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, problems
+}
+
+// mtgoDeck is the XML document of an MTGO .dek file
+type mtgoDeck struct {
+	XMLName              xml.Name   `xml:"Deck"`
+	XSD                  string     `xml:"xmlns:xsd,attr,omitempty"`
+	XSI                  string     `xml:"xmlns:xsi,attr,omitempty"`
+	NetDeckID            int        `xml:"NetDeckID"`
+	PreconstructedDeckID int        `xml:"PreconstructedDeckID"`
+	Cards                []mtgoCard `xml:"Cards"`
+}
+
+// mtgoCard is one card entry of an MTGO .dek file
+type mtgoCard struct {
+	CatID      string `xml:"CatID,attr,omitempty"`
+	Quantity   int    `xml:"Quantity,attr"`
+	Sideboard  bool   `xml:"Sideboard,attr"`
+	Name       string `xml:"Name,attr"`
+	Annotation int    `xml:"Annotation,attr"`
+}
+
+// parseMTGO parses an MTGO .dek file. Line numbers count the card entries.
+func parseMTGO(text string) ([]*Line, []*Problem, error) {
+	var deck mtgoDeck
+	if err := xml.Unmarshal([]byte(text), &deck); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidList, err)
+	}
+
+	var lines []*Line
+	var problems []*Problem
+	for index, card := range deck.Cards {
+		entry := fmt.Sprintf("%d %s", card.Quantity, card.Name)
+		if card.Quantity < 1 || strings.TrimSpace(card.Name) == "" {
+			problems = append(problems, &Problem{Line: index + 1, Text: entry, Reason: "card entry needs a name and a quantity of at least 1"})
+			continue
+		}
+
+		zone := models.ZoneMain
+		if card.Sideboard {
+			zone = models.ZoneSideboard
+		}
+		lines = append(lines, &Line{
+			Number:   index + 1,
+			Text:     entry,
+			Quantity: card.Quantity,
+			Name:     card.Name,
+			Zone:     zone,
+		})
+	}
+
+	return lines, problems, nil
+}
+
+// resolveMTGCard finds the card a deck list line names, returning uuid.Nil if there is none.
+// The printing named by set and collector number is preferred, then any printing in the named
+// set, then the latest regular printing of the name.
+func (s *Service) resolveMTGCard(ctx context.Context, line *Line) (uuid.UUID, error) {
+	name := normalizeMTGName(line.Name)
+
+	if line.SetCode != "" {
+		setCode, ok := arenaSetCodes[strings.ToUpper(line.SetCode)]
+		if !ok {
+			setCode = strings.ToLower(line.SetCode)
+		}
+
+		if line.CardNumber != "" {
+			cardID, err := s.findMTGCard(ctx, `
+				SELECT c.id
+				FROM cards c
+				JOIN mtg_cards m ON m.card_id = c.id
+				WHERE c.game = 'mtg' AND c.set_code = $1 AND c.number = $2
+				LIMIT 1
+			`, setCode, line.CardNumber)
+			if err != nil || cardID != uuid.Nil {
+				return cardID, err
+			}
+		}
+
+		cardID, err := s.findMTGCard(ctx, `
+			SELECT c.id
+			FROM cards c
+			JOIN mtg_cards m ON m.card_id = c.id
+			WHERE c.game = 'mtg' AND c.set_code = $1
+			AND (LOWER(c.name) = LOWER($2) OR LOWER(c.name) LIKE LOWER($2) || ' // %')
+			ORDER BY m.promo, c.number
+			LIMIT 1
+		`, setCode, name)
+		if err != nil || cardID != uuid.Nil {
+			return cardID, err
+		}
+	}
+
+	// Double-faced cards are often listed by their front face alone
+	return s.findMTGCard(ctx, `
+		SELECT c.id
+		FROM cards c
+		JOIN mtg_cards m ON m.card_id = c.id
+		WHERE c.game = 'mtg'
+		AND (LOWER(c.name) = LOWER($1) OR LOWER(c.name) LIKE LOWER($1) || ' // %')
+		ORDER BY m.promo, m.released_at DESC
+		LIMIT 1
+	`, name)
+}
+
+// findMTGCard runs a query selecting one card ID, returning uuid.Nil if it finds none
+func (s *Service) findMTGCard(ctx context.Context, query string, args ...interface{}) (uuid.UUID, error) {
+	var cardID uuid.UUID
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&cardID)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to find mtg card: %w", err)
+	}
+	return cardID, nil
+}
+
+// normalizeMTGName converts MTGO's "Fire/Ice" to Scryfall's "Fire // Ice"
+func normalizeMTGName(name string) string {
+	name = strings.TrimSpace(name)
+	if strings.Contains(name, "/") && !strings.Contains(name, "//") {
+		parts := strings.Split(name, "/")
+		for i, part := range parts {
+			parts[i] = strings.TrimSpace(part)
+		}
+		return strings.Join(parts, " // ")
+	}
+	return name
+}
+
+// mtgDeckCard is a deck card with the data an MTG deck list needs
+type mtgDeckCard struct {
+	Zone     string
+	Quantity int
+	Name     string
+	SetCode  string
+	Number   string
+}
+
+// loadMTGCards fetches the cards of a deck ordered by name
+func (s *Service) loadMTGCards(ctx context.Context, deckID uuid.UUID) ([]*mtgDeckCard, error) {
+	query := `
+		SELECT dc.zone, dc.quantity, c.name, c.set_code, c.number
+		FROM deck_cards dc
+		JOIN cards c ON c.id = dc.card_id
+		WHERE dc.deck_id = $1
+		ORDER BY c.name
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, deckID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load deck cards: %w", err)
+	}
+	defer rows.Close()
+
+	var cards []*mtgDeckCard
+	for rows.Next() {
+		card := &mtgDeckCard{}
+		if err := rows.Scan(&card.Zone, &card.Quantity, &card.Name, &card.SetCode, &card.Number); err != nil {
+			return nil, fmt.Errorf("failed to scan deck card: %w", err)
+		}
+		cards = append(cards, card)
+	}
+
+	return cards, rows.Err()
+}
+
+// renderMTG writes deck cards in an MTG deck list format, defaulting to Arena's
+func renderMTG(cards []*mtgDeckCard, format string) (string, error) {
+	switch format {
+	case "", mtgFormatArena:
+		return renderMTGText(cards, true), nil
+	case mtgFormatText:
+		return renderMTGText(cards, false), nil
+	case mtgFormatMTGO:
+		return renderMTGO(cards)
+	default:
+		return "", fmt.Errorf("%w: mtg has no %s format", ErrUnsupportedFormat, format)
+	}
+}
+
+// renderMTGText writes a list with a header per zone. Arena lists name each printing and leave
+// out the maybeboard, which Arena does not accept.
+func renderMTGText(cards []*mtgDeckCard, arena bool) string {
+	byZone := make(map[string][]*mtgDeckCard)
+	for _, card := range cards {
+		byZone[card.Zone] = append(byZone[card.Zone], card)
+	}
+
+	var sections []string
+	for _, section := range mtgZoneSections {
+		zoneCards := byZone[section.zone]
+		if len(zoneCards) == 0 || (arena && section.zone == models.ZoneMaybeboard) {
+			continue
+		}
+
+		var b strings.Builder
+		b.WriteString(section.header + "\n")
+		for _, card := range zoneCards {
+			if arena {
+				fmt.Fprintf(&b, "%d %s (%s) %s\n", card.Quantity, card.Name, arenaSetCode(card.SetCode), card.Number)
+			} else {
+				fmt.Fprintf(&b, "%d %s\n", card.Quantity, card.Name)
+			}
+		}
+		sections = append(sections, b.String())
+	}
+
+	return strings.Join(sections, "\n")
+}
+
+// arenaSetCode returns the code MTG Arena uses for a Scryfall set code
+func arenaSetCode(setCode string) string {
+	for arena, scryfall := range arenaSetCodes {
+		if strings.EqualFold(setCode, scryfall) {
+			return arena
+		}
+	}
+	return strings.ToUpper(setCode)
+}
+
+// renderMTGO writes an MTGO .dek file. MTGO only knows a main deck and a sideboard, so
+// commanders and companions go to the sideboard as MTGO expects, and the maybeboard is left out.
+func renderMTGO(cards []*mtgDeckCard) (string, error) {
+	deck := mtgoDeck{
+		XSD: "http://www.w3.org/2001/XMLSchema",
+		XSI: "http://www.w3.org/2001/XMLSchema-instance",
+	}
+	for _, card := range cards {
+		if card.Zone == models.ZoneMaybeboard {
+			continue
+		}
+		deck.Cards = append(deck.Cards, mtgoCard{
+			Quantity:  card.Quantity,
+			Sideboard: card.Zone != models.ZoneMain,
+			Name:      card.Name,
+		})
+	}
+
+	data, err := xml.MarshalIndent(deck, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to write mtgo deck: %w", err)
+	}
+	return xml.Header + string(data) + "\n", nil
+}
@@ -0,0 +1,282 @@
+package decklist
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// pokemonFormats lists the Pokémon deck list formats. PTCG Live reads and writes the same
+// text as the older PTCGO client.
+var pokemonFormats = map[string]bool{
+	"ptcgl": true,
+	"ptcgo": true,
+}
+
+// ptcglSetCodes maps the set abbreviations used by PTCG Live and PTCGO to Pokémon TCG API set IDs
+var ptcglSetCodes = map[string]string{
+	// Scarlet & Violet
+	"SVI": "sv1", "PAL": "sv2", "OBF": "sv3", "MEW": "sv3pt5", "PAR": "sv4", "PAF": "sv4pt5",
+	"TEF": "sv5", "TWM": "sv6", "SFA": "sv6pt5", "SCR": "sv7", "SSP": "sv8", "PRE": "sv8pt5",
+	"JTG": "sv9", "DRI": "sv10", "BLK": "zsv10pt5", "WHT": "rsv10pt5",
+	"SVP": "svp", "PR-SV": "svp", "SVE": "sve",
+	// Sword & Shield
+	"SSH": "swsh1", "RCL": "swsh2", "DAA": "swsh3", "CPA": "swsh35", "VIV": "swsh4", "SHF": "swsh45",
+	"BST": "swsh5", "CRE": "swsh6", "EVS": "swsh7", "CEL": "cel25", "FST": "swsh8", "BRS": "swsh9",
+	"ASR": "swsh10", "PGO": "pgo", "LOR": "swsh11", "SIT": "swsh12", "CRZ": "swsh12pt5",
+	"SWSHP": "swshp", "PR-SW": "swshp",
+	// Sun & Moon
+	"SUM": "sm1", "GRI": "sm2", "BUS": "sm3", "SLG": "sm35", "CIN": "sm4", "UPR": "sm5", "FLI": "sm6",
+	"CES": "sm7", "DRM": "sm75", "LOT": "sm8", "TEU": "sm9", "DET": "det1", "UNB": "sm10",
+	"UNM": "sm11", "HIF": "sm115", "CEC": "sm12", "SMP": "smp", "PR-SM": "smp",
+}
+
+// pokemonSetCodesByID is the reverse of ptcglSetCodes, preferring the PTCG Live abbreviation
+var pokemonSetCodesByID = func() map[string]string {
+	byID := make(map[string]string, len(ptcglSetCodes))
+	for code, id := range ptcglSetCodes {
+		if existing, ok := byID[id]; !ok || strings.HasPrefix(existing, "PR-") {
+			byID[id] = code
+		}
+	}
+	return byID
+}()
+
+// energySymbols maps the type symbols PTCG Live writes in basic Energy names to type names
+var energySymbols = strings.NewReplacer(
+	"{G}", "Grass", "{R}", "Fire", "{W}", "Water", "{L}", "Lightning",
+	"{P}", "Psychic", "{F}", "Fighting", "{D}", "Darkness", "{M}", "Metal",
+)
+
+var (
+	// ptcglHeader matches section headers such as "Pokémon: 12" or PTCGO's "##Trainer Cards - 36"
+	ptcglHeader = regexp.MustCompile(`(?i)^#*\s*(pok[eé]mon|trainer|energy|total)(\s+cards)?\s*[:\-]\s*\d+$`)
+	// ptcglCard matches "4 Charizard ex OBF 125", with an optional PTCGO "* " prefix
+	ptcglCard = regexp.MustCompile(`^(?:\*\s*)?(\d+)x?\s+(.+?)\s+([A-Za-z][A-Za-z0-9-]*)\s+([A-Za-z]*\d+[A-Za-z]*)$`)
+	// ptcglNameOnly matches lines without a set, such as "4 Iono"
+	ptcglNameOnly = regexp.MustCompile(`^(?:\*\s*)?(\d+)x?\s+(.+)$`)
+	// basicEnergyName matches the names basic Energy cards are printed and listed under
+	basicEnergyName = regexp.MustCompile(`(?i)^(?:basic\s+)?(grass|fire|water|lightning|psychic|fighting|darkness|metal|fairy)\s+energy$`)
+	// cardNumberPrefix matches the letters before a subset number, such as "TG" in "TG05"
+	cardNumberPrefix = regexp.MustCompile(`^([A-Za-z]+)\d`)
+)
+
+// parsePTCGL parses a PTCG Live or PTCGO deck list, reporting lines it does not understand
+func parsePTCGL(text string) ([]*Line, []*Problem) {
+	var lines []*Line
+	var problems []*Problem
+
+	for index, raw := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" || ptcglHeader.MatchString(trimmed) {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "***") {
+			continue
+		}
+
+		line := &Line{Number: index + 1, Text: trimmed}
+		if match := ptcglCard.FindStringSubmatch(trimmed); match != nil {
+			line.Name = match[2]
+			line.SetCode = match[3]
+			line.CardNumber = match[4]
+			line.Quantity, _ = strconv.Atoi(match[1])
+		} else if match := ptcglNameOnly.FindStringSubmatch(trimmed); match != nil {
+			line.Name = match[2]
+			line.Quantity, _ = strconv.Atoi(match[1])
+		} else {
+			problems = append(problems, &Problem{Line: line.Number, Text: trimmed, Reason: "expected a quantity followed by a card"})
+			continue
+		}
+
+		if line.Quantity < 1 {
+			problems = append(problems, &Problem{Line: line.Number, Text: trimmed, Reason: "quantity must be at least 1"})
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, problems
+}
+
+// resolvePokemonCard finds the card a deck list line names, returning uuid.Nil if there is none.
+// The printing named by set and number is preferred, falling back to any printing of the name.
+func (s *Service) resolvePokemonCard(ctx context.Context, line *Line) (uuid.UUID, error) {
+	name := strings.TrimSpace(energySymbols.Replace(line.Name))
+
+	if line.SetCode != "" {
+		for _, setID := range pokemonSetCandidates(line.SetCode, line.CardNumber) {
+			cardID, err := s.findPokemonCard(ctx, `
+				SELECT c.id
+				FROM cards c
+				JOIN pokemon_cards p ON p.card_id = c.id
+				WHERE c.game = 'pokemon' AND c.set_code = $1
+				AND (c.number = $2 OR LTRIM(c.number, '0') = LTRIM($2, '0'))
+				LIMIT 1
+			`, setID, line.CardNumber)
+			if err != nil || cardID != uuid.Nil {
+				return cardID, err
+			}
+		}
+	}
+
+	// Basic Energy is listed under several names and set codes; any printing will do,
+	// preferring the current Scarlet & Violet one
+	if match := basicEnergyName.FindStringSubmatch(name); match != nil {
+		energyType := strings.ToUpper(match[1][:1]) + strings.ToLower(match[1][1:])
+		return s.findPokemonCard(ctx, `
+			SELECT c.id
+			FROM cards c
+			JOIN pokemon_cards p ON p.card_id = c.id
+			WHERE c.game = 'pokemon' AND p.supertype = 'Energy'
+			AND 'Basic' = ANY(p.subtypes)
+			AND c.name IN ($1, $2)
+			ORDER BY c.set_code = 'sve' DESC, c.created_at DESC
+			LIMIT 1
+		`, energyType+" Energy", "Basic "+energyType+" Energy")
+	}
+
+	return s.findPokemonCard(ctx, `
+		SELECT c.id
+		FROM cards c
+		JOIN pokemon_cards p ON p.card_id = c.id
+		WHERE c.game = 'pokemon' AND LOWER(c.name) = LOWER($1)
+		ORDER BY c.created_at DESC
+		LIMIT 1
+	`, name)
+}
+
+// findPokemonCard runs a query selecting one card ID, returning uuid.Nil if it finds none
+func (s *Service) findPokemonCard(ctx context.Context, query string, args ...interface{}) (uuid.UUID, error) {
+	var cardID uuid.UUID
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&cardID)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to find pokemon card: %w", err)
+	}
+	return cardID, nil
+}
+
+// pokemonSetCandidates returns the Pokémon TCG API set IDs a deck list set code may refer to.
+// Subsets such as the Trainer Gallery are separate API sets whose numbers carry a prefix, so
+// "BRS TG05" is looked up in both swsh9 and swsh9tg.
+func pokemonSetCandidates(setCode, cardNumber string) []string {
+	setID, ok := ptcglSetCodes[strings.ToUpper(setCode)]
+	if !ok {
+		setID = strings.ToLower(setCode)
+	}
+
+	candidates := []string{setID}
+	if match := cardNumberPrefix.FindStringSubmatch(cardNumber); match != nil {
+		candidates = append(candidates, setID+strings.ToLower(match[1]))
+	}
+	return candidates
+}
+
+// pokemonDeckCard is a deck card with the data a PTCG Live list needs
+type pokemonDeckCard struct {
+	Quantity  int
+	Name      string
+	SetCode   string
+	Number    string
+	Supertype string
+}
+
+// loadPokemonCards fetches the cards of a deck with their Pokémon supertype
+func (s *Service) loadPokemonCards(ctx context.Context, deckID uuid.UUID) ([]*pokemonDeckCard, error) {
+	query := `
+		SELECT dc.quantity, c.name, c.set_code, c.number, COALESCE(p.supertype, '')
+		FROM deck_cards dc
+		JOIN cards c ON c.id = dc.card_id
+		LEFT JOIN pokemon_cards p ON p.card_id = c.id
+		WHERE dc.deck_id = $1
+		ORDER BY dc.quantity DESC, c.name
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, deckID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load deck cards: %w", err)
+	}
+	defer rows.Close()
+
+	var cards []*pokemonDeckCard
+	for rows.Next() {
+		card := &pokemonDeckCard{}
+		if err := rows.Scan(&card.Quantity, &card.Name, &card.SetCode, &card.Number, &card.Supertype); err != nil {
+			return nil, fmt.Errorf("failed to scan deck card: %w", err)
+		}
+		cards = append(cards, card)
+	}
+
+	return cards, rows.Err()
+}
+
+// renderPTCGL writes deck cards as a PTCG Live deck list, grouped into Pokémon, Trainer and Energy
+// and keeping the order of the cards within each group
+func renderPTCGL(cards []*pokemonDeckCard) string {
+	sections := []string{"Pokémon", "Trainer", "Energy"}
+	bySection := make(map[string][]*pokemonDeckCard)
+	counts := make(map[string]int)
+	total := 0
+
+	for _, card := range cards {
+		section := card.Supertype
+		switch {
+		case strings.EqualFold(section, "Pokémon"), strings.EqualFold(section, "Pokemon"):
+			section = "Pokémon"
+		case strings.EqualFold(section, "Energy"):
+			section = "Energy"
+		case strings.EqualFold(section, "Trainer"):
+			section = "Trainer"
+		case strings.HasSuffix(card.Name, " Energy"):
+			// Cards without Pokémon data are placed by name
+			section = "Energy"
+		default:
+			section = "Trainer"
+		}
+
+		bySection[section] = append(bySection[section], card)
+		counts[section] += card.Quantity
+		total += card.Quantity
+	}
+
+	var b strings.Builder
+	for _, section := range sections {
+		sectionCards := bySection[section]
+		if len(sectionCards) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "%s: %d\n", section, counts[section])
+		for _, card := range sectionCards {
+			fmt.Fprintf(&b, "%d %s %s %s\n", card.Quantity, card.Name, ptcglSetCode(card.SetCode), card.Number)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "Total Cards: %d\n", total)
+
+	return b.String()
+}
+
+// ptcglSetCode returns the PTCG Live abbreviation of a Pokémon TCG API set ID. Subsets such as
+// swsh9tg use the abbreviation of their parent set, and unknown sets are written as their ID.
+func ptcglSetCode(setID string) string {
+	id := strings.ToLower(setID)
+	if code, ok := pokemonSetCodesByID[id]; ok {
+		return code
+	}
+	for _, suffix := range []string{"tg", "gg", "sv"} {
+		if code, ok := pokemonSetCodesByID[strings.TrimSuffix(id, suffix)]; ok && strings.HasSuffix(id, suffix) {
+			return code
+		}
+	}
+	return strings.ToUpper(setID)
+}
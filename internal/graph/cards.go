@@ -0,0 +1,44 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shiftregister-vg/card-craft/internal/cards"
+	"github.com/shiftregister-vg/card-craft/internal/graph/model"
+	"github.com/shiftregister-vg/card-craft/internal/search"
+	"github.com/shiftregister-vg/card-craft/internal/utils"
+)
+
+// cardPageOptions validates the paging and sorting arguments of a card list query, taking
+// defaultFirst cards when first is not given
+func cardPageOptions(first *int, defaultFirst int, after *string, sortBy *model.CardSortField, sortOrder *model.SortOrder) (cards.PageOptions, error) {
+	opts := cards.PageOptions{First: defaultFirst, After: utils.DerefString(after)}
+	if first != nil {
+		if *first < 1 || *first > search.MaxPageSize {
+			return opts, NewValidationError(fmt.Sprintf("first must be between 1 and %d", search.MaxPageSize)).
+				WithField("first", "out of range")
+		}
+		opts.First = *first
+	}
+	if sortBy != nil {
+		opts.SortBy = string(*sortBy)
+	}
+	if sortOrder != nil {
+		opts.SortOrder = string(*sortOrder)
+	}
+	return opts, nil
+}
+
+// cardPageError maps the errors of a sorted card query to GraphQL errors
+func cardPageError(err error, field string) error {
+	switch {
+	case errors.Is(err, cards.ErrInvalidCursor):
+		return NewValidationError("invalid cursor").
+			WithField("after", fmt.Sprintf("must be a cursor returned by %s with the same sort", field))
+	case errors.Is(err, cards.ErrInvalidSort):
+		return NewValidationError(err.Error()).WithField("sortBy", "unknown sort")
+	default:
+		return NewInternalError("failed to search cards")
+	}
+}
@@ -0,0 +1,192 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shiftregister-vg/card-craft/internal/auth"
+	"github.com/shiftregister-vg/card-craft/internal/models"
+	"github.com/shiftregister-vg/card-craft/internal/ownership"
+)
+
+// findDeck loads a deck by its ID, mapping lookup failures to GraphQL errors
+func (r *Resolver) findDeck(id string) (*models.Deck, error) {
+	deckID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, NewInvalidIDError(id)
+	}
+
+	deck, err := r.deckStore.FindByID(deckID)
+	if err != nil {
+		return nil, NewInternalError("failed to load deck")
+	}
+	if deck == nil {
+		return nil, NewNotFoundError("deck", id)
+	}
+
+	return deck, nil
+}
+
+// findOwnedDeck loads a deck and verifies it belongs to the authenticated user
+func (r *Resolver) findOwnedDeck(ctx context.Context, id string) (*models.Deck, error) {
+	user := auth.GetUserFromContext(ctx)
+	if user == nil {
+		return nil, NewUnauthorizedError("not authenticated")
+	}
+
+	deck, err := r.findDeck(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if deck.UserID != user.ID {
+		return nil, NewForbiddenError("you do not have permission to modify this deck")
+	}
+
+	return deck, nil
+}
+
+// findReadableDeck loads a deck that is either shared, as a public or unlisted deck, or owned by
+// the authenticated user. Private decks belonging to other users are reported as not found so
+// their existence is not leaked.
+func (r *Resolver) findReadableDeck(ctx context.Context, id string) (*models.Deck, error) {
+	deck, err := r.findDeck(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if deck.IsShared() {
+		return deck, nil
+	}
+
+	user := auth.GetUserFromContext(ctx)
+	if user == nil || deck.UserID != user.ID {
+		return nil, NewNotFoundError("deck", id)
+	}
+
+	return deck, nil
+}
+
+// findOwnedDeckCard loads a deck card and verifies its deck belongs to the authenticated user
+func (r *Resolver) findOwnedDeckCard(ctx context.Context, id string) (*models.DeckCard, error) {
+	deckCardID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, NewInvalidIDError(id)
+	}
+
+	deckCard, err := r.deckStore.GetDeckCard(deckCardID)
+	if err != nil {
+		return nil, NewInternalError("failed to load deck card")
+	}
+	if deckCard == nil {
+		return nil, NewNotFoundError("deck card", id)
+	}
+
+	if _, err := r.findOwnedDeck(ctx, deckCard.DeckID.String()); err != nil {
+		return nil, err
+	}
+
+	return deckCard, nil
+}
+
+// deckCardName returns the name of the card behind a deck card, for revision messages
+func (r *Resolver) deckCardName(ctx context.Context, deckCard *models.DeckCard) string {
+	card, err := r.cardStore.FindByID(ctx, deckCard.CardID)
+	if err != nil || card == nil {
+		return "a card"
+	}
+	return card.Name
+}
+
+// deckZone resolves the zone a card is placed in, defaulting to the main deck, and checks
+// the deck's game allows it
+func deckZone(game string, zone *string) (string, error) {
+	if zone == nil || *zone == "" {
+		return models.ZoneMain, nil
+	}
+
+	z := strings.ToLower(*zone)
+	if !models.IsValidZone(game, z) {
+		return "", NewValidationError(fmt.Sprintf("%s decks have no %s zone", game, *zone)).
+			WithField("zone", "must be one of "+strings.Join(models.ZonesForGame(game), ", "))
+	}
+
+	return z, nil
+}
+
+// findReadableRevision loads a deck revision whose deck the authenticated user may read
+func (r *Resolver) findReadableRevision(ctx context.Context, id string) (*models.DeckRevision, error) {
+	revisionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, NewInvalidIDError(id)
+	}
+
+	revision, err := r.deckStore.FindRevision(revisionID)
+	if err != nil {
+		return nil, NewInternalError("failed to load deck revision")
+	}
+	if revision == nil {
+		return nil, NewNotFoundError("deck revision", id)
+	}
+
+	if _, err := r.findReadableDeck(ctx, revision.DeckID.String()); err != nil {
+		// Revisions of decks the user cannot read are reported as missing themselves
+		return nil, NewNotFoundError("deck revision", id)
+	}
+
+	return revision, nil
+}
+
+// findDeckCardModel loads the card a deck entry refers to
+func (r *Resolver) findDeckCardModel(ctx context.Context, cardID uuid.UUID) (*models.Card, error) {
+	card, err := r.cardStore.FindByID(ctx, cardID)
+	if err != nil {
+		return nil, NewInternalError("failed to load card")
+	}
+	if card == nil {
+		return nil, NewNotFoundError("card", cardID.String())
+	}
+	return card, nil
+}
+
+// compareWithCollections compares a deck with the authenticated user's collections, limited to
+// the given collection IDs when there are any
+func (r *Resolver) compareWithCollections(ctx context.Context, deck *models.Deck, collectionIds []string) (*ownership.Result, error) {
+	user := auth.GetUserFromContext(ctx)
+	if user == nil {
+		return nil, NewUnauthorizedError("not authenticated")
+	}
+
+	ids := make([]uuid.UUID, len(collectionIds))
+	for i, id := range collectionIds {
+		collectionID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, NewInvalidIDError(id)
+		}
+		ids[i] = collectionID
+	}
+
+	result, err := r.ownership.Compare(ctx, deck, user.ID, ids)
+	if err != nil {
+		return nil, NewInternalError("failed to compare deck with collections")
+	}
+
+	return result, nil
+}
+
+// deckVisibility resolves the visibility requested for a deck, keeping current when none is given
+func deckVisibility(visibility *string, current string) (string, error) {
+	if visibility == nil {
+		return current, nil
+	}
+
+	v := strings.ToLower(strings.TrimSpace(*visibility))
+	if !models.IsValidVisibility(v) {
+		return "", NewValidationError(fmt.Sprintf("unknown deck visibility %s", *visibility)).
+			WithField("visibility", "must be private, unlisted or public")
+	}
+
+	return v, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/shiftregister-vg/card-craft/internal/search"
 	"github.com/vektah/gqlparser/v2/ast"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 )
@@ -18,8 +19,9 @@ const (
 	ErrorCodeForbidden    ErrorCode = "FORBIDDEN"
 
 	// Validation errors
-	ErrorCodeValidation ErrorCode = "VALIDATION_ERROR"
-	ErrorCodeInvalidID  ErrorCode = "INVALID_ID"
+	ErrorCodeValidation  ErrorCode = "VALIDATION_ERROR"
+	ErrorCodeInvalidID   ErrorCode = "INVALID_ID"
+	ErrorCodeQuerySyntax ErrorCode = "QUERY_SYNTAX_ERROR"
 
 	// Resource errors
 	ErrorCodeNotFound      ErrorCode = "NOT_FOUND"
@@ -35,6 +37,8 @@ type GraphQLError struct {
 	Message string
 	Path    []string
 	Fields  map[string]string
+	// Extensions holds additional details reported alongside the code
+	Extensions map[string]interface{}
 }
 
 // Error implements the error interface
@@ -63,19 +67,31 @@ func (e *GraphQLError) WithField(field, message string) *GraphQLError {
 	return e
 }
 
+// WithExtension adds a detail to the error's extensions
+func (e *GraphQLError) WithExtension(key string, value interface{}) *GraphQLError {
+	if e.Extensions == nil {
+		e.Extensions = make(map[string]interface{})
+	}
+	e.Extensions[key] = value
+	return e
+}
+
 // ErrorPresenter is a GraphQL error presenter that formats our custom errors
 func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
 	if gqlErr, ok := err.(*GraphQLError); ok {
-		extensions := map[string]interface{}{
-			"code": gqlErr.Code,
+		extensions := map[string]interface{}{}
+		for key, value := range gqlErr.Extensions {
+			extensions[key] = value
 		}
+		extensions["code"] = gqlErr.Code
 		if len(gqlErr.Fields) > 0 {
 			extensions["fields"] = gqlErr.Fields
 		}
 
-		path := make(ast.Path, len(gqlErr.Path))
-		for i, p := range gqlErr.Path {
-			path[i] = ast.PathName(p)
+		// Leave the path nil when none was set so gqlgen fills in the resolver path
+		var path ast.Path
+		for _, p := range gqlErr.Path {
+			path = append(path, ast.PathName(p))
 		}
 
 		return &gqlerror.Error{
@@ -112,6 +128,15 @@ func NewValidationError(message string) *GraphQLError {
 	return NewGraphQLError(ErrorCodeValidation, message)
 }
 
+// NewQuerySyntaxError creates an error for a search query that could not be parsed, reporting
+// where in the query the problem is
+func NewQuerySyntaxError(err *search.ParseError) *GraphQLError {
+	return NewGraphQLError(ErrorCodeQuerySyntax, err.Error()).
+		WithField("query", err.Message).
+		WithExtension("position", err.Position).
+		WithExtension("token", err.Token)
+}
+
 // NewInvalidIDError creates a new invalid ID error
 func NewInvalidIDError(id string) *GraphQLError {
 	return NewGraphQLError(ErrorCodeInvalidID, fmt.Sprintf("invalid ID: %s", id))
@@ -0,0 +1,47 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shiftregister-vg/card-craft/internal/auth"
+	"github.com/shiftregister-vg/card-craft/internal/cards"
+	"github.com/shiftregister-vg/card-craft/internal/jobs"
+	"github.com/shiftregister-vg/card-craft/internal/models"
+)
+
+// requireAdmin returns the authenticated user, refusing users who are not admins. action
+// completes the error messages, as in "you must be logged in to start an import".
+func requireAdmin(ctx context.Context, action string) (*models.User, error) {
+	user := auth.GetUserFromContext(ctx)
+	if user == nil {
+		return nil, NewUnauthorizedError("you must be logged in to " + action)
+	}
+	if !user.IsAdmin {
+		return nil, NewForbiddenError("only admins may " + action)
+	}
+	return user, nil
+}
+
+// newImporter creates the importer of a game, checking it can run
+func (r *Resolver) newImporter(game string) (cards.CardImporter, error) {
+	importer, err := r.importers.NewImporter(game, r.cardStore, r.db)
+	if errors.Is(err, cards.ErrUnknownGame) {
+		return nil, NewValidationError("unsupported game: "+game).WithField("game", "no importer is registered for this game")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := cards.CheckConfigured(importer); err != nil {
+		return nil, NewValidationError(err.Error()).WithField("game", "the importer for this game is not configured")
+	}
+	return importer, nil
+}
+
+// importError maps the errors of running an import job to GraphQL errors
+func importError(err error) error {
+	if errors.Is(err, jobs.ErrImportRunning) {
+		return NewValidationError(err.Error()).WithField("game", "an import of this game is already running")
+	}
+	return err
+}
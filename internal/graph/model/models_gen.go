@@ -2,8 +2,109 @@
 
 package model
 
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
 type Mutation struct {
 }
 
 type Query struct {
 }
+
+type Subscription struct {
+}
+
+type CardSortField string
+
+const (
+	CardSortFieldRelevance   CardSortField = "RELEVANCE"
+	CardSortFieldName        CardSortField = "NAME"
+	CardSortFieldSet         CardSortField = "SET"
+	CardSortFieldNumber      CardSortField = "NUMBER"
+	CardSortFieldRarity      CardSortField = "RARITY"
+	CardSortFieldReleaseDate CardSortField = "RELEASE_DATE"
+	CardSortFieldPrice       CardSortField = "PRICE"
+)
+
+var AllCardSortField = []CardSortField{
+	CardSortFieldRelevance,
+	CardSortFieldName,
+	CardSortFieldSet,
+	CardSortFieldNumber,
+	CardSortFieldRarity,
+	CardSortFieldReleaseDate,
+	CardSortFieldPrice,
+}
+
+func (e CardSortField) IsValid() bool {
+	switch e {
+	case CardSortFieldRelevance, CardSortFieldName, CardSortFieldSet, CardSortFieldNumber, CardSortFieldRarity, CardSortFieldReleaseDate, CardSortFieldPrice:
+		return true
+	}
+	return false
+}
+
+func (e CardSortField) String() string {
+	return string(e)
+}
+
+func (e *CardSortField) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CardSortField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CardSortField", str)
+	}
+	return nil
+}
+
+func (e CardSortField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SortOrder string
+
+const (
+	SortOrderAsc  SortOrder = "ASC"
+	SortOrderDesc SortOrder = "DESC"
+)
+
+var AllSortOrder = []SortOrder{
+	SortOrderAsc,
+	SortOrderDesc,
+}
+
+func (e SortOrder) IsValid() bool {
+	switch e {
+	case SortOrderAsc, SortOrderDesc:
+		return true
+	}
+	return false
+}
+
+func (e SortOrder) String() string {
+	return string(e)
+}
+
+func (e *SortOrder) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SortOrder(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SortOrder", str)
+	}
+	return nil
+}
+
+func (e SortOrder) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
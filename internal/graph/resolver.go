@@ -7,8 +7,14 @@ import (
 
 	"github.com/shiftregister-vg/card-craft/internal/auth"
 	"github.com/shiftregister-vg/card-craft/internal/cards"
+	"github.com/shiftregister-vg/card-craft/internal/decklist"
+	"github.com/shiftregister-vg/card-craft/internal/jobs"
 	"github.com/shiftregister-vg/card-craft/internal/models"
+	"github.com/shiftregister-vg/card-craft/internal/ownership"
 	"github.com/shiftregister-vg/card-craft/internal/search"
+	"github.com/shiftregister-vg/card-craft/internal/simulator"
+	"github.com/shiftregister-vg/card-craft/internal/stats"
+	"github.com/shiftregister-vg/card-craft/internal/validation"
 )
 
 // This file will not be regenerated automatically.
@@ -25,6 +31,13 @@ type Resolver struct {
 	collectionStore *models.CollectionStore
 	authService     *auth.Service
 	searchService   *search.SearchService
+	deckValidator   *validation.Service
+	deckLists       *decklist.Service
+	ownership       *ownership.Service
+	deckStats       *stats.Service
+	simulator       *simulator.Service
+	importers       *cards.Registry
+	jobService      *jobs.Service
 }
 
 // NewResolver creates a new resolver with the given dependencies
@@ -36,6 +49,7 @@ func NewResolver(
 	deckStore *models.DeckStore,
 	collectionStore *models.CollectionStore,
 	authService *auth.Service,
+	jobService *jobs.Service,
 ) *Resolver {
 	searchService := search.NewSearchService(cardStore)
 	return &Resolver{
@@ -47,5 +61,12 @@ func NewResolver(
 		collectionStore: collectionStore,
 		authService:     authService,
 		searchService:   searchService,
+		deckValidator:   validation.NewService(db),
+		deckLists:       decklist.NewService(db, deckStore),
+		ownership:       ownership.NewService(db),
+		deckStats:       stats.NewService(db),
+		simulator:       simulator.NewService(db),
+		importers:       cards.DefaultRegistry,
+		jobService:      jobService,
 	}
 }
@@ -9,6 +9,7 @@ import (
 	"context"
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
@@ -19,12 +20,29 @@ import (
 	"github.com/google/uuid"
 	"github.com/shiftregister-vg/card-craft/internal/auth"
 	"github.com/shiftregister-vg/card-craft/internal/cards"
+	"github.com/shiftregister-vg/card-craft/internal/decklist"
 	"github.com/shiftregister-vg/card-craft/internal/graph/generated"
+	"github.com/shiftregister-vg/card-craft/internal/graph/model"
+	"github.com/shiftregister-vg/card-craft/internal/jobs"
 	"github.com/shiftregister-vg/card-craft/internal/models"
+	"github.com/shiftregister-vg/card-craft/internal/ownership"
+	"github.com/shiftregister-vg/card-craft/internal/search"
+	"github.com/shiftregister-vg/card-craft/internal/simulator"
+	"github.com/shiftregister-vg/card-craft/internal/stats"
 	"github.com/shiftregister-vg/card-craft/internal/types"
 	"github.com/shiftregister-vg/card-craft/internal/utils"
+	"github.com/shiftregister-vg/card-craft/internal/validation"
 )
 
+// Capabilities is the resolver for the capabilities field.
+func (r *availableImporterResolver) Capabilities(ctx context.Context, obj *cards.ImporterStatus) ([]string, error) {
+	capabilities := make([]string, len(obj.Capabilities))
+	for i, capability := range obj.Capabilities {
+		capabilities[i] = string(capability)
+	}
+	return capabilities, nil
+}
+
 // ID is the resolver for the id field.
 func (r *cardResolver) ID(ctx context.Context, obj *models.Card) (string, error) {
 	return obj.ID.String(), nil
@@ -40,13 +58,13 @@ func (r *cardResolver) UpdatedAt(ctx context.Context, obj *models.Card) (string,
 	return obj.UpdatedAt.Format(time.RFC3339), nil
 }
 
-// Cards is the resolver for the cards field.
-func (r *cardSearchResultResolver) Cards(ctx context.Context, obj *types.CardSearchResult) ([]*models.Card, error) {
-	result := make([]*models.Card, len(obj.Cards))
-	for i, card := range obj.Cards {
-		result[i] = r.cardStore.ToModel(card)
+// ReleasedAt is the resolver for the releasedAt field.
+func (r *cardResolver) ReleasedAt(ctx context.Context, obj *models.Card) (*string, error) {
+	if obj.ReleasedAt == nil {
+		return nil, nil
 	}
-	return result, nil
+	releasedAt := obj.ReleasedAt.Format("2006-01-02")
+	return &releasedAt, nil
 }
 
 // ID is the resolver for the id field.
@@ -87,7 +105,7 @@ func (r *collectionCardResolver) CardID(ctx context.Context, obj *models.Collect
 // GameSpecificDetails is the resolver for the gameSpecificDetails field.
 func (r *collectionCardResolver) GameSpecificDetails(ctx context.Context, obj *models.CollectionCard) (*string, error) {
 	// Get the card to determine the game
-	card, err := r.cardStore.FindByID(obj.CardID)
+	card, err := r.cardStore.FindByID(ctx, obj.CardID)
 	if err != nil {
 		return nil, err
 	}
@@ -164,57 +182,249 @@ func (r *collectionCardResolver) UpdatedAt(ctx context.Context, obj *models.Coll
 
 // ID is the resolver for the id field.
 func (r *deckResolver) ID(ctx context.Context, obj *models.Deck) (string, error) {
-	panic(fmt.Errorf("not implemented: ID - id"))
+	return obj.ID.String(), nil
 }
 
 // UserID is the resolver for the userId field.
 func (r *deckResolver) UserID(ctx context.Context, obj *models.Deck) (string, error) {
-	panic(fmt.Errorf("not implemented: UserID - userId"))
+	return obj.UserID.String(), nil
 }
 
 // CreatedAt is the resolver for the createdAt field.
 func (r *deckResolver) CreatedAt(ctx context.Context, obj *models.Deck) (string, error) {
-	panic(fmt.Errorf("not implemented: CreatedAt - createdAt"))
+	return obj.CreatedAt.Format(time.RFC3339), nil
 }
 
 // UpdatedAt is the resolver for the updatedAt field.
 func (r *deckResolver) UpdatedAt(ctx context.Context, obj *models.Deck) (string, error) {
-	panic(fmt.Errorf("not implemented: UpdatedAt - updatedAt"))
+	return obj.UpdatedAt.Format(time.RFC3339), nil
 }
 
 // Cards is the resolver for the cards field.
 func (r *deckResolver) Cards(ctx context.Context, obj *models.Deck) ([]*models.DeckCard, error) {
-	panic(fmt.Errorf("not implemented: Cards - cards"))
+	deckCards, err := r.deckStore.GetCards(obj.ID)
+	if err != nil {
+		return nil, NewInternalError("failed to load deck cards")
+	}
+	if deckCards == nil {
+		deckCards = []*models.DeckCard{}
+	}
+	return deckCards, nil
+}
+
+// Zones is the resolver for the zones field.
+func (r *deckResolver) Zones(ctx context.Context, obj *models.Deck) ([]string, error) {
+	return models.ZonesForGame(obj.Game), nil
+}
+
+// ExportText is the resolver for the exportText field.
+func (r *deckResolver) ExportText(ctx context.Context, obj *models.Deck, format *string) (string, error) {
+	text, err := r.deckLists.Export(ctx, obj, utils.DerefString(format))
+	if errors.Is(err, decklist.ErrUnsupportedGame) || errors.Is(err, decklist.ErrUnsupportedFormat) {
+		return "", NewValidationError(err.Error()).WithField("format", "unsupported for "+obj.Game)
+	}
+	if err != nil {
+		return "", NewInternalError("failed to export deck")
+	}
+
+	return text, nil
+}
+
+// Ownership is the resolver for the ownership field.
+func (r *deckResolver) Ownership(ctx context.Context, obj *models.Deck, collectionIds []string) (*ownership.Result, error) {
+	return r.compareWithCollections(ctx, obj, collectionIds)
+}
+
+// ExportMissingCards is the resolver for the exportMissingCards field.
+func (r *deckResolver) ExportMissingCards(ctx context.Context, obj *models.Deck, collectionIds []string, format *string) (string, error) {
+	result, err := r.compareWithCollections(ctx, obj, collectionIds)
+	if err != nil {
+		return "", err
+	}
+
+	text, err := ownership.ExportMissing(result, obj.Game, utils.DerefString(format))
+	if errors.Is(err, ownership.ErrUnsupportedFormat) {
+		return "", NewValidationError(err.Error()).WithField("format", "must be csv or wantlist")
+	}
+	if err != nil {
+		return "", NewInternalError("failed to export missing cards")
+	}
+
+	return text, nil
+}
+
+// Stats is the resolver for the stats field.
+func (r *deckResolver) Stats(ctx context.Context, obj *models.Deck, drawCardIds []string, handSize *int) (*stats.DeckStats, error) {
+	drawCardIDs := make([]uuid.UUID, len(drawCardIds))
+	for i, id := range drawCardIds {
+		cardID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, NewInvalidIDError(id)
+		}
+		drawCardIDs[i] = cardID
+	}
+
+	size := 0
+	if handSize != nil {
+		if *handSize < 1 {
+			return nil, NewValidationError("hand size must be at least 1").WithField("handSize", "must be greater than zero")
+		}
+		size = *handSize
+	}
+
+	result, err := r.deckStats.Compute(ctx, obj, drawCardIDs, size)
+	if err != nil {
+		return nil, NewInternalError("failed to compute deck stats")
+	}
+
+	return result, nil
 }
 
 // ID is the resolver for the id field.
 func (r *deckCardResolver) ID(ctx context.Context, obj *models.DeckCard) (string, error) {
-	panic(fmt.Errorf("not implemented: ID - id"))
+	return obj.ID.String(), nil
 }
 
 // DeckID is the resolver for the deckId field.
 func (r *deckCardResolver) DeckID(ctx context.Context, obj *models.DeckCard) (string, error) {
-	panic(fmt.Errorf("not implemented: DeckID - deckId"))
+	return obj.DeckID.String(), nil
 }
 
 // CardID is the resolver for the cardId field.
 func (r *deckCardResolver) CardID(ctx context.Context, obj *models.DeckCard) (string, error) {
-	panic(fmt.Errorf("not implemented: CardID - cardId"))
+	return obj.CardID.String(), nil
 }
 
 // CreatedAt is the resolver for the createdAt field.
 func (r *deckCardResolver) CreatedAt(ctx context.Context, obj *models.DeckCard) (string, error) {
-	panic(fmt.Errorf("not implemented: CreatedAt - createdAt"))
+	return obj.CreatedAt.Format(time.RFC3339), nil
 }
 
 // UpdatedAt is the resolver for the updatedAt field.
 func (r *deckCardResolver) UpdatedAt(ctx context.Context, obj *models.DeckCard) (string, error) {
-	panic(fmt.Errorf("not implemented: UpdatedAt - updatedAt"))
+	return obj.UpdatedAt.Format(time.RFC3339), nil
 }
 
 // Card is the resolver for the card field.
 func (r *deckCardResolver) Card(ctx context.Context, obj *models.DeckCard) (*models.Card, error) {
-	panic(fmt.Errorf("not implemented: Card - card"))
+	card, err := r.cardStore.FindByID(ctx, obj.CardID)
+	if err != nil {
+		return nil, NewInternalError("failed to load card")
+	}
+	if card == nil {
+		return nil, NewNotFoundError("card", obj.CardID.String())
+	}
+	return card, nil
+}
+
+// CardID is the resolver for the cardId field.
+func (r *deckCardChangeResolver) CardID(ctx context.Context, obj *models.DeckCardChange) (string, error) {
+	return obj.CardID.String(), nil
+}
+
+// Card is the resolver for the card field.
+func (r *deckCardChangeResolver) Card(ctx context.Context, obj *models.DeckCardChange) (*models.Card, error) {
+	return r.findDeckCardModel(ctx, obj.CardID)
+}
+
+// CardID is the resolver for the cardId field.
+func (r *deckCardOwnershipResolver) CardID(ctx context.Context, obj *ownership.CardOwnership) (string, error) {
+	return obj.CardID.String(), nil
+}
+
+// Card is the resolver for the card field.
+func (r *deckCardOwnershipResolver) Card(ctx context.Context, obj *ownership.CardOwnership) (*models.Card, error) {
+	return r.findDeckCardModel(ctx, obj.CardID)
+}
+
+// FromRevisionID is the resolver for the fromRevisionId field.
+func (r *deckDiffResolver) FromRevisionID(ctx context.Context, obj *models.DeckDiff) (string, error) {
+	return obj.FromRevisionID.String(), nil
+}
+
+// ToRevisionID is the resolver for the toRevisionId field.
+func (r *deckDiffResolver) ToRevisionID(ctx context.Context, obj *models.DeckDiff) (*string, error) {
+	if obj.ToRevisionID == nil {
+		return nil, nil
+	}
+	id := obj.ToRevisionID.String()
+	return &id, nil
+}
+
+// DeckID is the resolver for the deckId field.
+func (r *deckOwnershipResolver) DeckID(ctx context.Context, obj *ownership.Result) (string, error) {
+	return obj.DeckID.String(), nil
+}
+
+// ID is the resolver for the id field.
+func (r *deckRevisionResolver) ID(ctx context.Context, obj *models.DeckRevision) (string, error) {
+	return obj.ID.String(), nil
+}
+
+// DeckID is the resolver for the deckId field.
+func (r *deckRevisionResolver) DeckID(ctx context.Context, obj *models.DeckRevision) (string, error) {
+	return obj.DeckID.String(), nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *deckRevisionResolver) CreatedAt(ctx context.Context, obj *models.DeckRevision) (string, error) {
+	return obj.CreatedAt.Format(time.RFC3339), nil
+}
+
+// Cards is the resolver for the cards field.
+func (r *deckRevisionResolver) Cards(ctx context.Context, obj *models.DeckRevision) ([]*models.DeckRevisionCard, error) {
+	cards, err := r.deckStore.GetRevisionCards(obj.ID)
+	if err != nil {
+		return nil, NewInternalError("failed to load deck revision cards")
+	}
+	if cards == nil {
+		cards = []*models.DeckRevisionCard{}
+	}
+	return cards, nil
+}
+
+// CardID is the resolver for the cardId field.
+func (r *deckRevisionCardResolver) CardID(ctx context.Context, obj *models.DeckRevisionCard) (string, error) {
+	return obj.CardID.String(), nil
+}
+
+// Card is the resolver for the card field.
+func (r *deckRevisionCardResolver) Card(ctx context.Context, obj *models.DeckRevisionCard) (*models.Card, error) {
+	return r.findDeckCardModel(ctx, obj.CardID)
+}
+
+// CardID is the resolver for the cardId field.
+func (r *drawProbabilityResolver) CardID(ctx context.Context, obj *stats.DrawProbability) (string, error) {
+	return obj.CardID.String(), nil
+}
+
+// ID is the resolver for the id field.
+func (r *importJobResolver) ID(ctx context.Context, obj *jobs.Job) (string, error) {
+	return obj.ID.String(), nil
+}
+
+// Trigger is the resolver for the trigger field.
+func (r *importJobResolver) Trigger(ctx context.Context, obj *jobs.Job) (string, error) {
+	return string(obj.Trigger), nil
+}
+
+// Status is the resolver for the status field.
+func (r *importJobResolver) Status(ctx context.Context, obj *jobs.Job) (string, error) {
+	return string(obj.Status), nil
+}
+
+// StartedAt is the resolver for the startedAt field.
+func (r *importJobResolver) StartedAt(ctx context.Context, obj *jobs.Job) (string, error) {
+	return obj.StartedAt.Format(time.RFC3339), nil
+}
+
+// FinishedAt is the resolver for the finishedAt field.
+func (r *importJobResolver) FinishedAt(ctx context.Context, obj *jobs.Job) (*string, error) {
+	if obj.FinishedAt == nil {
+		return nil, nil
+	}
+	finishedAt := obj.FinishedAt.Format(time.RFC3339)
+	return &finishedAt, nil
 }
 
 // Register is the resolver for the register field.
@@ -261,32 +471,269 @@ func (r *mutationResolver) DeleteCard(ctx context.Context, id string) (bool, err
 
 // CreateDeck is the resolver for the createDeck field.
 func (r *mutationResolver) CreateDeck(ctx context.Context, input types.DeckInput) (*models.Deck, error) {
-	panic(fmt.Errorf("not implemented: CreateDeck - createDeck"))
+	user := auth.GetUserFromContext(ctx)
+	if user == nil {
+		return nil, NewUnauthorizedError("not authenticated")
+	}
+
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, NewValidationError("deck name is required").WithField("name", "must not be empty")
+	}
+	if strings.TrimSpace(input.Game) == "" {
+		return nil, NewValidationError("deck game is required").WithField("game", "must not be empty")
+	}
+
+	visibility, err := deckVisibility(input.Visibility, models.VisibilityPrivate)
+	if err != nil {
+		return nil, err
+	}
+
+	deck := &models.Deck{
+		ID:          uuid.New(),
+		UserID:      user.ID,
+		Name:        input.Name,
+		Description: utils.DerefString(input.Description),
+		Game:        input.Game,
+		Visibility:  visibility,
+	}
+
+	if err := r.deckStore.Create(ctx, deck); err != nil {
+		return nil, NewInternalError("failed to create deck")
+	}
+
+	return deck, nil
 }
 
 // UpdateDeck is the resolver for the updateDeck field.
 func (r *mutationResolver) UpdateDeck(ctx context.Context, id string, input types.DeckInput) (*models.Deck, error) {
-	panic(fmt.Errorf("not implemented: UpdateDeck - updateDeck"))
+	deck, err := r.findOwnedDeck(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, NewValidationError("deck name is required").WithField("name", "must not be empty")
+	}
+	if !strings.EqualFold(input.Game, deck.Game) {
+		return nil, NewValidationError("the game of an existing deck cannot be changed").WithField("game", "must match the deck's game")
+	}
+
+	visibility, err := deckVisibility(input.Visibility, deck.Visibility)
+	if err != nil {
+		return nil, err
+	}
+
+	deck.Name = input.Name
+	deck.Description = utils.DerefString(input.Description)
+	deck.Visibility = visibility
+
+	if err := r.deckStore.Update(deck); err != nil {
+		return nil, NewInternalError("failed to update deck")
+	}
+
+	return deck, nil
 }
 
 // DeleteDeck is the resolver for the deleteDeck field.
 func (r *mutationResolver) DeleteDeck(ctx context.Context, id string) (bool, error) {
-	panic(fmt.Errorf("not implemented: DeleteDeck - deleteDeck"))
+	deck, err := r.findOwnedDeck(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.deckStore.DeleteWithCards(ctx, deck.ID); err != nil {
+		return false, NewInternalError("failed to delete deck")
+	}
+
+	return true, nil
 }
 
 // AddCardToDeck is the resolver for the addCardToDeck field.
 func (r *mutationResolver) AddCardToDeck(ctx context.Context, deckID string, input types.DeckCardInput) (*models.DeckCard, error) {
-	panic(fmt.Errorf("not implemented: AddCardToDeck - addCardToDeck"))
+	deck, err := r.findOwnedDeck(ctx, deckID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Quantity < 1 {
+		return nil, NewValidationError("quantity must be at least 1").WithField("quantity", "must be greater than zero")
+	}
+
+	cardUUID, err := uuid.Parse(input.CardID)
+	if err != nil {
+		return nil, NewInvalidIDError(input.CardID)
+	}
+
+	card, err := r.cardStore.FindByID(ctx, cardUUID)
+	if err != nil {
+		return nil, NewInternalError("failed to load card")
+	}
+	if card == nil {
+		return nil, NewNotFoundError("card", input.CardID)
+	}
+
+	if !strings.EqualFold(card.Game, deck.Game) {
+		return nil, NewValidationError(fmt.Sprintf("card belongs to %s, but the deck is for %s", card.Game, deck.Game)).WithField("cardId", "card game does not match deck game")
+	}
+
+	zone, err := deckZone(deck.Game, input.Zone)
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Added %d %s to %s", input.Quantity, card.Name, zone)
+	deckCard, err := r.deckStore.AddCard(ctx, deck.ID, card.ID, input.Quantity, zone, message)
+	if err != nil {
+		return nil, NewInternalError("failed to add card to deck")
+	}
+
+	return deckCard, nil
 }
 
 // UpdateDeckCard is the resolver for the updateDeckCard field.
-func (r *mutationResolver) UpdateDeckCard(ctx context.Context, id string, quantity int) (*models.DeckCard, error) {
-	panic(fmt.Errorf("not implemented: UpdateDeckCard - updateDeckCard"))
+func (r *mutationResolver) UpdateDeckCard(ctx context.Context, id string, quantity int, zone *string) (*models.DeckCard, error) {
+	deckCard, err := r.findOwnedDeckCard(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if quantity < 1 {
+		return nil, NewValidationError("quantity must be at least 1").WithField("quantity", "must be greater than zero")
+	}
+
+	deck, err := r.findDeck(deckCard.DeckID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	target := deckCard.Zone
+	if zone != nil {
+		if target, err = deckZone(deck.Game, zone); err != nil {
+			return nil, err
+		}
+	}
+
+	if target != deckCard.Zone {
+		deckCards, err := r.deckStore.GetCards(deck.ID)
+		if err != nil {
+			return nil, NewInternalError("failed to load deck cards")
+		}
+		for _, other := range deckCards {
+			if other.CardID == deckCard.CardID && other.Zone == target {
+				return nil, NewValidationError(fmt.Sprintf("the card is already in the %s zone", target)).WithField("zone", "card is already in this zone")
+			}
+		}
+	}
+
+	name := r.deckCardName(ctx, deckCard)
+	message := fmt.Sprintf("Set %s to %d in %s", name, quantity, target)
+	if target != deckCard.Zone {
+		message = fmt.Sprintf("Moved %d %s from %s to %s", quantity, name, deckCard.Zone, target)
+	}
+	if err := r.deckStore.UpdateDeckCard(ctx, deckCard, quantity, target, message); err != nil {
+		return nil, NewInternalError("failed to update deck card")
+	}
+
+	updated, err := r.deckStore.GetDeckCard(deckCard.ID)
+	if err != nil || updated == nil {
+		return nil, NewInternalError("failed to load deck card")
+	}
+
+	return updated, nil
 }
 
 // RemoveCardFromDeck is the resolver for the removeCardFromDeck field.
 func (r *mutationResolver) RemoveCardFromDeck(ctx context.Context, id string) (bool, error) {
-	panic(fmt.Errorf("not implemented: RemoveCardFromDeck - removeCardFromDeck"))
+	deckCard, err := r.findOwnedDeckCard(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	message := fmt.Sprintf("Removed %s from %s", r.deckCardName(ctx, deckCard), deckCard.Zone)
+	if err := r.deckStore.RemoveCard(ctx, deckCard.DeckID, deckCard.CardID, deckCard.Zone, message); err != nil {
+		return false, NewInternalError("failed to remove card from deck")
+	}
+
+	return true, nil
+}
+
+// SaveDeckRevision is the resolver for the saveDeckRevision field.
+func (r *mutationResolver) SaveDeckRevision(ctx context.Context, deckID string, message *string) (*models.DeckRevision, error) {
+	deck, err := r.findOwnedDeck(ctx, deckID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := r.deckStore.CreateRevision(ctx, deck.ID, utils.DerefString(message))
+	if err != nil {
+		return nil, NewInternalError("failed to save deck revision")
+	}
+
+	return revision, nil
+}
+
+// RestoreDeckRevision is the resolver for the restoreDeckRevision field.
+func (r *mutationResolver) RestoreDeckRevision(ctx context.Context, revisionID string, message *string) (*models.Deck, error) {
+	revision, err := r.findReadableRevision(ctx, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	deck, err := r.findOwnedDeck(ctx, revision.DeckID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	msg := utils.DerefString(message)
+	if msg == "" {
+		msg = fmt.Sprintf("Restored revision %d", revision.Number)
+	}
+
+	if _, err := r.deckStore.RestoreRevision(ctx, deck, revision, msg); err != nil {
+		return nil, NewInternalError("failed to restore deck revision")
+	}
+
+	return deck, nil
+}
+
+// ImportDeckList is the resolver for the importDeckList field.
+func (r *mutationResolver) ImportDeckList(ctx context.Context, game string, text string, name *string, format *string) (*decklist.ImportResult, error) {
+	user := auth.GetUserFromContext(ctx)
+	if user == nil {
+		return nil, NewUnauthorizedError("not authenticated")
+	}
+
+	if strings.TrimSpace(text) == "" {
+		return nil, NewValidationError("deck list is required").WithField("text", "must not be empty")
+	}
+
+	deckName := strings.TrimSpace(utils.DerefString(name))
+	if deckName == "" {
+		deckName = "Imported deck"
+	}
+
+	deck := &models.Deck{
+		ID:     uuid.New(),
+		UserID: user.ID,
+		Name:   deckName,
+		Game:   game,
+	}
+
+	result, err := r.deckLists.Import(ctx, deck, utils.DerefString(format), text)
+	switch {
+	case errors.Is(err, decklist.ErrUnsupportedGame):
+		return nil, NewValidationError(err.Error()).WithField("game", "deck lists are not supported for this game")
+	case errors.Is(err, decklist.ErrUnsupportedFormat):
+		return nil, NewValidationError(err.Error()).WithField("format", "unsupported for "+game)
+	case errors.Is(err, decklist.ErrInvalidList):
+		return nil, NewValidationError(err.Error()).WithField("text", "could not be read")
+	case errors.Is(err, decklist.ErrNoCards):
+		return nil, NewValidationError(err.Error()).WithField("text", "no line matched a known card")
+	case err != nil:
+		return nil, NewInternalError("failed to import deck list")
+	}
+
+	return result, nil
 }
 
 // ImportCollection is the resolver for the importCollection field.
@@ -397,7 +844,7 @@ func (r *mutationResolver) AddCardToCollection(ctx context.Context, collectionID
 		return nil, fmt.Errorf("not authorized")
 	}
 
-	card, err := r.cardStore.FindByID(cardUUID)
+	card, err := r.cardStore.FindByID(ctx, cardUUID)
 	if err != nil {
 		return nil, err
 	}
@@ -410,7 +857,7 @@ func (r *mutationResolver) AddCardToCollection(ctx context.Context, collectionID
 		ID:           uuid.New(),
 		CollectionID: collectionUUID,
 		CardID:       cardUUID,
-		Card:         r.cardStore.ToModel(card),
+		Card:         card,
 		Quantity:     input.Quantity,
 		IsFoil:       utils.DerefBool(input.IsFoil),
 		Condition:    utils.DerefString(input.Condition),
@@ -505,21 +952,82 @@ func (r *mutationResolver) RemoveCardFromCollection(ctx context.Context, id stri
 
 // ImportCards is the resolver for the importCards field.
 func (r *mutationResolver) ImportCards(ctx context.Context, game string) (bool, error) {
-	var importer cards.CardImporter
-	switch game {
-	case "pokemon":
-		importer = cards.NewPokemonImporter(r.cardStore, r.pokemonStore)
-	default:
-		return false, fmt.Errorf("unsupported game: %s", game)
+	user, err := requireAdmin(ctx, "import cards")
+	if err != nil {
+		return false, err
 	}
 
-	if err := importer.Import(ctx, r.cardStore); err != nil {
+	importer, err := r.newImporter(game)
+	if err != nil {
 		return false, err
 	}
 
+	_, err = r.jobService.Run(ctx, game, jobs.TriggerAPI, user.ID, func(ctx context.Context) error {
+		return importer.Import(ctx, r.cardStore)
+	})
+	if err != nil {
+		return false, importError(err)
+	}
+
 	return true, nil
 }
 
+// StartImport is the resolver for the startImport field.
+func (r *mutationResolver) StartImport(ctx context.Context, game string) (*jobs.Job, error) {
+	user, err := requireAdmin(ctx, "start an import")
+	if err != nil {
+		return nil, err
+	}
+
+	importer, err := r.newImporter(game)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := r.jobService.Start(ctx, game, jobs.TriggerAPI, user.ID, func(ctx context.Context) error {
+		return importer.Import(ctx, r.cardStore)
+	})
+	if err != nil {
+		return nil, importError(err)
+	}
+	return job, nil
+}
+
+// CancelImport is the resolver for the cancelImport field.
+func (r *mutationResolver) CancelImport(ctx context.Context, jobID string) (*jobs.Job, error) {
+	user := auth.GetUserFromContext(ctx)
+	if user == nil {
+		return nil, NewUnauthorizedError("you must be logged in to cancel an import")
+	}
+
+	id, err := uuid.Parse(jobID)
+	if err != nil {
+		return nil, NewInvalidIDError(jobID)
+	}
+
+	job, err := r.jobService.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, NewNotFoundError("import job", jobID)
+	}
+	// Admins may cancel any import, other users only the ones they started
+	if !user.IsAdmin && (job.StartedBy == nil || *job.StartedBy != user.ID) {
+		return nil, NewForbiddenError("only admins or the user who started an import may cancel it")
+	}
+
+	job, err = r.jobService.Cancel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, NewNotFoundError("import job", jobID)
+	}
+
+	return job, nil
+}
+
 // BulkImportCardsToCollection is the resolver for the bulkImportCardsToCollection field.
 func (r *mutationResolver) BulkImportCardsToCollection(ctx context.Context, collectionID string, file graphql.Upload) (*models.BulkImportResult, error) {
 	user := auth.GetUserFromContext(ctx)
@@ -615,7 +1123,7 @@ func (r *mutationResolver) BulkImportCardsToCollection(ctx context.Context, coll
 		}
 
 		// Find the card - ensure game is pokemon and all comparisons are case-insensitive
-		card, err := r.cardStore.FindByGameAndNumber("pokemon", setCode, number)
+		card, err := r.cardStore.FindByGameAndNumber(ctx, "pokemon", setCode, number)
 		if err != nil {
 			errors = append(errors, &models.ImportError{
 				CardID:  fmt.Sprintf("%s/%s", setCode, number),
@@ -637,7 +1145,7 @@ func (r *mutationResolver) BulkImportCardsToCollection(ctx context.Context, coll
 			ID:           uuid.New(),
 			CollectionID: collectionUUID,
 			CardID:       card.ID,
-			Card:         r.cardStore.ToModel(card),
+			Card:         card,
 			Quantity:     quantity,
 			IsFoil:       isFoil,
 			Condition:    condition,
@@ -662,57 +1170,61 @@ func (r *mutationResolver) BulkImportCardsToCollection(ctx context.Context, coll
 	}, nil
 }
 
-// Card is the resolver for the card field.
-func (r *queryResolver) Card(ctx context.Context, id string) (*models.Card, error) {
-	panic(fmt.Errorf("not implemented: Card - card"))
+// CollectionID is the resolver for the collectionId field.
+func (r *ownedCopiesResolver) CollectionID(ctx context.Context, obj *ownership.OwnedCopies) (string, error) {
+	return obj.CollectionID.String(), nil
 }
 
-// CardsByGame is the resolver for the cardsByGame field.
-func (r *queryResolver) CardsByGame(ctx context.Context, game string, first *int, after *string) (*models.CardConnection, error) {
-	limit := 100 // Default to 100 cards per page
-	if first != nil {
-		limit = *first
-	}
+// CardID is the resolver for the cardId field.
+func (r *ownedCopiesResolver) CardID(ctx context.Context, obj *ownership.OwnedCopies) (string, error) {
+	return obj.CardID.String(), nil
+}
 
-	afterCursor := ""
-	if after != nil {
-		afterCursor = *after
+// Card is the resolver for the card field.
+func (r *queryResolver) Card(ctx context.Context, id string) (*models.Card, error) {
+	cardID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, NewInvalidIDError(id)
 	}
+	return r.cardStore.FindByID(ctx, cardID)
+}
 
-	typeCards, nextCursor, err := r.cardStore.FindByGame(game, limit, afterCursor)
+// CardsByGame is the resolver for the cardsByGame field.
+func (r *queryResolver) CardsByGame(ctx context.Context, game string, first *int, after *string, sortBy *model.CardSortField, sortOrder *model.SortOrder) (*models.CardConnection, error) {
+	opts, err := cardPageOptions(first, 100, after, sortBy, sortOrder)
 	if err != nil {
 		return nil, err
 	}
 
-	edges := make([]*models.CardEdge, len(typeCards))
-	for i, card := range typeCards {
-		modelCard := r.cardStore.ToModel(card)
-		edges[i] = &models.CardEdge{
-			Node:   modelCard,
-			Cursor: fmt.Sprintf("%s:%s", card.SetCode, card.Number),
-		}
+	page, err := r.cardStore.FindByGame(ctx, game, opts)
+	if err != nil {
+		return nil, cardPageError(err, "cardsByGame")
 	}
 
-	return &models.CardConnection{
-		Edges: edges,
-		PageInfo: &models.PageInfo{
-			HasNextPage: nextCursor != "",
-			EndCursor:   &nextCursor,
-		},
-	}, nil
+	return search.CardConnection(page), nil
 }
 
 // CardsBySet is the resolver for the cardsBySet field.
 func (r *queryResolver) CardsBySet(ctx context.Context, game string, setCode string) ([]*models.Card, error) {
-	panic(fmt.Errorf("not implemented: CardsBySet - cardsBySet"))
+	return r.cardStore.FindBySet(ctx, game, setCode)
 }
 
 // SearchCards is the resolver for the searchCards field.
-func (r *queryResolver) SearchCards(ctx context.Context, game *string, setCode *string, rarity *string, name *string, page *int, pageSize *int, sortBy *string, sortOrder *string) (*types.CardSearchResult, error) {
+func (r *queryResolver) SearchCards(ctx context.Context, game *string, setCode *string, rarity *string, name *string, page *int, pageSize *int, sortBy *model.CardSortField, sortOrder *model.SortOrder, first *int, after *string) (*types.CardSearchResult, error) {
 	if game == nil {
 		return nil, fmt.Errorf("game is required")
 	}
 
+	if page != nil && after != nil {
+		return nil, NewValidationError("page and after cannot be combined").
+			WithField("page", "cannot be combined with after")
+	}
+
+	paging, err := cardPageOptions(first, 0, after, sortBy, sortOrder)
+	if err != nil {
+		return nil, err
+	}
+
 	opts := types.SearchOptions{
 		Game:      *game,
 		SetCode:   utils.DerefString(setCode),
@@ -720,16 +1232,41 @@ func (r *queryResolver) SearchCards(ctx context.Context, game *string, setCode *
 		Name:      utils.DerefString(name),
 		Page:      utils.DerefInt(page),
 		PageSize:  utils.DerefInt(pageSize),
-		SortBy:    utils.DerefString(sortBy),
-		SortOrder: utils.DerefString(sortOrder),
+		SortBy:    paging.SortBy,
+		SortOrder: paging.SortOrder,
+		First:     paging.First,
+		After:     paging.After,
 	}
 
-	return r.searchService.Search(opts)
+	result, err := r.searchService.Search(ctx, opts)
+	if err != nil {
+		return nil, cardPageError(err, "searchCards")
+	}
+	return result, nil
+}
+
+// AdvancedSearch is the resolver for the advancedSearch field.
+func (r *queryResolver) AdvancedSearch(ctx context.Context, query string, first *int, after *string, sortBy *model.CardSortField, sortOrder *model.SortOrder) (*models.CardConnection, error) {
+	opts, err := cardPageOptions(first, 20, after, sortBy, sortOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := r.searchService.AdvancedSearch(ctx, query, opts)
+	var parseErr *search.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, NewQuerySyntaxError(parseErr)
+	}
+	if err != nil {
+		return nil, cardPageError(err, "advancedSearch")
+	}
+
+	return search.CardConnection(page), nil
 }
 
 // CardFilters is the resolver for the cardFilters field.
 func (r *queryResolver) CardFilters(ctx context.Context, game string) (*types.CardFilters, error) {
-	panic(fmt.Errorf("not implemented: CardFilters - cardFilters"))
+	return r.searchService.Filters(ctx, game)
 }
 
 // CollectionCard is the resolver for the collectionCard field.
@@ -749,17 +1286,256 @@ func (r *queryResolver) CollectionCard(ctx context.Context, id string) (*models.
 
 // Deck is the resolver for the deck field.
 func (r *queryResolver) Deck(ctx context.Context, id string) (*models.Deck, error) {
-	panic(fmt.Errorf("not implemented: Deck - deck"))
+	return r.findReadableDeck(ctx, id)
 }
 
 // MyDecks is the resolver for the myDecks field.
 func (r *queryResolver) MyDecks(ctx context.Context) ([]*models.Deck, error) {
-	panic(fmt.Errorf("not implemented: MyDecks - myDecks"))
+	user := auth.GetUserFromContext(ctx)
+	if user == nil {
+		return nil, NewUnauthorizedError("not authenticated")
+	}
+
+	decks, err := r.deckStore.FindByUserID(user.ID)
+	if err != nil {
+		return nil, NewInternalError("failed to load decks")
+	}
+	if decks == nil {
+		decks = []*models.Deck{}
+	}
+	return decks, nil
+}
+
+// PublicDeck is the resolver for the publicDeck field.
+func (r *queryResolver) PublicDeck(ctx context.Context, slug string) (*models.Deck, error) {
+	deck, err := r.deckStore.FindBySlug(slug)
+	if err != nil {
+		return nil, NewInternalError("failed to load deck")
+	}
+	if deck == nil || !deck.IsShared() {
+		return nil, NewNotFoundError("deck", slug)
+	}
+
+	return deck, nil
+}
+
+// PublicDecks is the resolver for the publicDecks field.
+func (r *queryResolver) PublicDecks(ctx context.Context, game *string, filters *types.PublicDeckFilters, first *int, after *string) (*models.DeckConnection, error) {
+	limit := 20
+	if first != nil {
+		if *first < 1 || *first > 100 {
+			return nil, NewValidationError("first must be between 1 and 100").WithField("first", "must be between 1 and 100")
+		}
+		limit = *first
+	}
+
+	filter := models.PublicDeckFilter{Game: utils.DerefString(game)}
+	if filters != nil {
+		filter.Query = strings.TrimSpace(utils.DerefString(filters.Query))
+		if filters.CardID != nil {
+			cardID, err := uuid.Parse(*filters.CardID)
+			if err != nil {
+				return nil, NewInvalidIDError(*filters.CardID)
+			}
+			filter.CardID = &cardID
+		}
+	}
+
+	decks, nextCursor, err := r.deckStore.FindPublic(filter, limit, utils.DerefString(after))
+	if errors.Is(err, models.ErrInvalidCursor) {
+		return nil, NewValidationError("invalid cursor").WithField("after", "must be a cursor returned by publicDecks")
+	}
+	if err != nil {
+		return nil, NewInternalError("failed to load public decks")
+	}
+
+	edges := make([]*models.DeckEdge, len(decks))
+	for i, deck := range decks {
+		edges[i] = &models.DeckEdge{Node: deck, Cursor: models.DeckCursor(deck)}
+	}
+
+	pageInfo := &models.PageInfo{HasNextPage: nextCursor != ""}
+	if len(edges) > 0 {
+		pageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+
+	return &models.DeckConnection{Edges: edges, PageInfo: pageInfo}, nil
 }
 
 // DeckCards is the resolver for the deckCards field.
 func (r *queryResolver) DeckCards(ctx context.Context, deckID string) ([]*models.DeckCard, error) {
-	panic(fmt.Errorf("not implemented: DeckCards - deckCards"))
+	deck, err := r.findReadableDeck(ctx, deckID)
+	if err != nil {
+		return nil, err
+	}
+
+	deckCards, err := r.deckStore.GetCards(deck.ID)
+	if err != nil {
+		return nil, NewInternalError("failed to load deck cards")
+	}
+	if deckCards == nil {
+		deckCards = []*models.DeckCard{}
+	}
+	return deckCards, nil
+}
+
+// ValidateDeck is the resolver for the validateDeck field.
+func (r *queryResolver) ValidateDeck(ctx context.Context, id string, format string) (*validation.Result, error) {
+	deck, err := r.findReadableDeck(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.deckValidator.ValidateDeck(ctx, deck, format)
+	if errors.Is(err, validation.ErrUnsupportedFormat) {
+		return nil, NewValidationError(err.Error()).WithField("format", "unsupported for "+deck.Game)
+	}
+	if err != nil {
+		return nil, NewInternalError("failed to validate deck")
+	}
+
+	return result, nil
+}
+
+// SimulateDeck is the resolver for the simulateDeck field.
+func (r *queryResolver) SimulateDeck(ctx context.Context, deckID string, iterations *int, seed *int) (*simulator.Result, error) {
+	deck, err := r.findReadableDeck(ctx, deckID)
+	if err != nil {
+		return nil, err
+	}
+
+	n := simulator.DefaultIterations
+	if iterations != nil {
+		if *iterations < 1 || *iterations > simulator.MaxIterations {
+			return nil, NewValidationError(fmt.Sprintf("iterations must be between 1 and %d", simulator.MaxIterations)).
+				WithField("iterations", "out of range")
+		}
+		n = *iterations
+	}
+
+	// Without a seed each run differs; the seed used is returned so the run can be repeated
+	s := simulator.RandomSeed()
+	if seed != nil {
+		s = *seed
+	}
+
+	result, err := r.simulator.SimulateDeck(ctx, deck, n, s)
+	if errors.Is(err, simulator.ErrDeckTooSmall) || errors.Is(err, simulator.ErrNoBasicPokemon) {
+		return nil, NewValidationError(err.Error()).WithField("deckId", "deck cannot be simulated")
+	}
+	if err != nil {
+		return nil, NewInternalError("failed to simulate deck")
+	}
+
+	return result, nil
+}
+
+// DeckRevisions is the resolver for the deckRevisions field.
+func (r *queryResolver) DeckRevisions(ctx context.Context, deckID string) ([]*models.DeckRevision, error) {
+	deck, err := r.findReadableDeck(ctx, deckID)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := r.deckStore.ListRevisions(deck.ID)
+	if err != nil {
+		return nil, NewInternalError("failed to load deck revisions")
+	}
+	if revisions == nil {
+		revisions = []*models.DeckRevision{}
+	}
+	return revisions, nil
+}
+
+// DeckRevision is the resolver for the deckRevision field.
+func (r *queryResolver) DeckRevision(ctx context.Context, id string) (*models.DeckRevision, error) {
+	return r.findReadableRevision(ctx, id)
+}
+
+// DeckDiff is the resolver for the deckDiff field.
+func (r *queryResolver) DeckDiff(ctx context.Context, fromRevisionID string, toRevisionID *string) (*models.DeckDiff, error) {
+	from, err := r.findReadableRevision(ctx, fromRevisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromCards, err := r.deckStore.GetRevisionCards(from.ID)
+	if err != nil {
+		return nil, NewInternalError("failed to load deck revision cards")
+	}
+
+	diff := &models.DeckDiff{FromRevisionID: from.ID}
+
+	var toCards []*models.DeckRevisionCard
+	if toRevisionID != nil {
+		to, err := r.findReadableRevision(ctx, *toRevisionID)
+		if err != nil {
+			return nil, err
+		}
+		if to.DeckID != from.DeckID {
+			return nil, NewValidationError("revisions belong to different decks").WithField("toRevisionId", "must be a revision of the same deck")
+		}
+
+		toCards, err = r.deckStore.GetRevisionCards(to.ID)
+		if err != nil {
+			return nil, NewInternalError("failed to load deck revision cards")
+		}
+		diff.ToRevisionID = &to.ID
+	} else {
+		deckCards, err := r.deckStore.GetCards(from.DeckID)
+		if err != nil {
+			return nil, NewInternalError("failed to load deck cards")
+		}
+		toCards = models.RevisionCards(deckCards)
+	}
+
+	diff.Added, diff.Removed, diff.Changed = models.DiffDeckCards(fromCards, toCards)
+	return diff, nil
+}
+
+// ImportJobs is the resolver for the importJobs field.
+func (r *queryResolver) ImportJobs(ctx context.Context, game *string, limit *int) ([]*jobs.Job, error) {
+	if auth.GetUserFromContext(ctx) == nil {
+		return nil, NewUnauthorizedError("you must be logged in to view import jobs")
+	}
+
+	gameFilter := ""
+	if game != nil {
+		gameFilter = *game
+	}
+
+	pageSize := 20
+	if limit != nil {
+		if *limit < 1 || *limit > 100 {
+			return nil, NewValidationError("invalid limit").WithField("limit", "must be between 1 and 100")
+		}
+		pageSize = *limit
+	}
+
+	return r.jobService.List(ctx, gameFilter, pageSize)
+}
+
+// ImportJob is the resolver for the importJob field.
+func (r *queryResolver) ImportJob(ctx context.Context, id string) (*jobs.Job, error) {
+	if auth.GetUserFromContext(ctx) == nil {
+		return nil, NewUnauthorizedError("you must be logged in to view import jobs")
+	}
+
+	jobID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, NewInvalidIDError(id)
+	}
+
+	return r.jobService.FindByID(ctx, jobID)
+}
+
+// AvailableImporters is the resolver for the availableImporters field.
+func (r *queryResolver) AvailableImporters(ctx context.Context) ([]*cards.ImporterStatus, error) {
+	if auth.GetUserFromContext(ctx) == nil {
+		return nil, NewUnauthorizedError("you must be logged in to view importers")
+	}
+
+	return r.importers.Statuses(r.cardStore, r.db), nil
 }
 
 // Me is the resolver for the me field.
@@ -798,6 +1574,38 @@ func (r *queryResolver) CollectionCards(ctx context.Context, collectionID string
 	return r.collectionStore.GetCards(uuid)
 }
 
+// CardID is the resolver for the cardId field.
+func (r *simulatedCardResolver) CardID(ctx context.Context, obj *simulator.CardResult) (string, error) {
+	return obj.CardID.String(), nil
+}
+
+// DeckID is the resolver for the deckId field.
+func (r *simulationResultResolver) DeckID(ctx context.Context, obj *simulator.Result) (string, error) {
+	return obj.DeckID.String(), nil
+}
+
+// ImportProgress is the resolver for the importProgress field.
+func (r *subscriptionResolver) ImportProgress(ctx context.Context, jobID string) (<-chan *jobs.Job, error) {
+	if auth.GetUserFromContext(ctx) == nil {
+		return nil, NewUnauthorizedError("you must be logged in to follow an import")
+	}
+
+	id, err := uuid.Parse(jobID)
+	if err != nil {
+		return nil, NewInvalidIDError(jobID)
+	}
+
+	updates, err := r.jobService.Subscribe(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if updates == nil {
+		return nil, NewNotFoundError("import job", jobID)
+	}
+
+	return updates, nil
+}
+
 // ID is the resolver for the id field.
 func (r *userResolver) ID(ctx context.Context, obj *models.User) (string, error) {
 	return obj.ID.String(), nil
@@ -813,14 +1621,14 @@ func (r *userResolver) UpdatedAt(ctx context.Context, obj *models.User) (string,
 	panic(fmt.Errorf("not implemented: UpdatedAt - updatedAt"))
 }
 
+// AvailableImporter returns generated.AvailableImporterResolver implementation.
+func (r *Resolver) AvailableImporter() generated.AvailableImporterResolver {
+	return &availableImporterResolver{r}
+}
+
 // Card returns generated.CardResolver implementation.
 func (r *Resolver) Card() generated.CardResolver { return &cardResolver{r} }
 
-// CardSearchResult returns generated.CardSearchResultResolver implementation.
-func (r *Resolver) CardSearchResult() generated.CardSearchResultResolver {
-	return &cardSearchResultResolver{r}
-}
-
 // Collection returns generated.CollectionResolver implementation.
 func (r *Resolver) Collection() generated.CollectionResolver { return &collectionResolver{r} }
 
@@ -835,21 +1643,79 @@ func (r *Resolver) Deck() generated.DeckResolver { return &deckResolver{r} }
 // DeckCard returns generated.DeckCardResolver implementation.
 func (r *Resolver) DeckCard() generated.DeckCardResolver { return &deckCardResolver{r} }
 
+// DeckCardChange returns generated.DeckCardChangeResolver implementation.
+func (r *Resolver) DeckCardChange() generated.DeckCardChangeResolver {
+	return &deckCardChangeResolver{r}
+}
+
+// DeckCardOwnership returns generated.DeckCardOwnershipResolver implementation.
+func (r *Resolver) DeckCardOwnership() generated.DeckCardOwnershipResolver {
+	return &deckCardOwnershipResolver{r}
+}
+
+// DeckDiff returns generated.DeckDiffResolver implementation.
+func (r *Resolver) DeckDiff() generated.DeckDiffResolver { return &deckDiffResolver{r} }
+
+// DeckOwnership returns generated.DeckOwnershipResolver implementation.
+func (r *Resolver) DeckOwnership() generated.DeckOwnershipResolver { return &deckOwnershipResolver{r} }
+
+// DeckRevision returns generated.DeckRevisionResolver implementation.
+func (r *Resolver) DeckRevision() generated.DeckRevisionResolver { return &deckRevisionResolver{r} }
+
+// DeckRevisionCard returns generated.DeckRevisionCardResolver implementation.
+func (r *Resolver) DeckRevisionCard() generated.DeckRevisionCardResolver {
+	return &deckRevisionCardResolver{r}
+}
+
+// DrawProbability returns generated.DrawProbabilityResolver implementation.
+func (r *Resolver) DrawProbability() generated.DrawProbabilityResolver {
+	return &drawProbabilityResolver{r}
+}
+
+// ImportJob returns generated.ImportJobResolver implementation.
+func (r *Resolver) ImportJob() generated.ImportJobResolver { return &importJobResolver{r} }
+
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
+// OwnedCopies returns generated.OwnedCopiesResolver implementation.
+func (r *Resolver) OwnedCopies() generated.OwnedCopiesResolver { return &ownedCopiesResolver{r} }
+
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+// SimulatedCard returns generated.SimulatedCardResolver implementation.
+func (r *Resolver) SimulatedCard() generated.SimulatedCardResolver { return &simulatedCardResolver{r} }
+
+// SimulationResult returns generated.SimulationResultResolver implementation.
+func (r *Resolver) SimulationResult() generated.SimulationResultResolver {
+	return &simulationResultResolver{r}
+}
+
+// Subscription returns generated.SubscriptionResolver implementation.
+func (r *Resolver) Subscription() generated.SubscriptionResolver { return &subscriptionResolver{r} }
+
 // User returns generated.UserResolver implementation.
 func (r *Resolver) User() generated.UserResolver { return &userResolver{r} }
 
+type availableImporterResolver struct{ *Resolver }
 type cardResolver struct{ *Resolver }
-type cardSearchResultResolver struct{ *Resolver }
 type collectionResolver struct{ *Resolver }
 type collectionCardResolver struct{ *Resolver }
 type deckResolver struct{ *Resolver }
 type deckCardResolver struct{ *Resolver }
+type deckCardChangeResolver struct{ *Resolver }
+type deckCardOwnershipResolver struct{ *Resolver }
+type deckDiffResolver struct{ *Resolver }
+type deckOwnershipResolver struct{ *Resolver }
+type deckRevisionResolver struct{ *Resolver }
+type deckRevisionCardResolver struct{ *Resolver }
+type drawProbabilityResolver struct{ *Resolver }
+type importJobResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
+type ownedCopiesResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type simulatedCardResolver struct{ *Resolver }
+type simulationResultResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
 type userResolver struct{ *Resolver }
@@ -37,6 +37,9 @@ func NewServer(resolver *graph.Resolver) http.Handler {
 	srv.AddTransport(transport.POST{})
 	srv.AddTransport(transport.MultipartForm{})
 
+	// Report GraphQL error codes in the extensions of each error
+	srv.SetErrorPresenter(graph.ErrorPresenter)
+
 	// Configure query caching
 	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
 
@@ -1,26 +1,29 @@
 package importers
 
 import (
+	"context"
 	"encoding/csv"
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/shiftregister-vg/card-craft/internal/cards"
 	"github.com/shiftregister-vg/card-craft/internal/models"
 )
 
 type TCGCollectorImporter struct {
-	cardStore *models.CardStore
+	cardStore *cards.CardStore
 }
 
-func NewTCGCollectorImporter(cardStore *models.CardStore) *TCGCollectorImporter {
+func NewTCGCollectorImporter(cardStore *cards.CardStore) *TCGCollectorImporter {
 	return &TCGCollectorImporter{
 		cardStore: cardStore,
 	}
 }
 
-func (i *TCGCollectorImporter) Import(reader io.Reader) (*models.ImportResult, error) {
+func (i *TCGCollectorImporter) Import(ctx context.Context, reader io.Reader) (*models.ImportResult, error) {
 	csvReader := csv.NewReader(reader)
 
 	// Skip header row
@@ -63,9 +66,11 @@ func (i *TCGCollectorImporter) Import(reader io.Reader) (*models.ImportResult, e
 			Rarity:  strings.TrimSpace(record[4]),
 			Game:    strings.TrimSpace(record[5]),
 		}
+		now := time.Now()
+		card.CreatedAt, card.UpdatedAt = now, now
 
 		// Check if card already exists
-		existingCard, err := i.cardStore.FindByGameSetAndNumber(card.Game, card.SetCode, card.Number)
+		existingCard, err := i.cardStore.FindByGameAndNumber(ctx, card.Game, card.SetCode, card.Number)
 		if err != nil {
 			result.Errors = append(result.Errors, fmt.Sprintf("Error checking for existing card: %v", err))
 			continue
@@ -76,15 +81,16 @@ func (i *TCGCollectorImporter) Import(reader io.Reader) (*models.ImportResult, e
 			existingCard.Name = card.Name
 			existingCard.SetName = card.SetName
 			existingCard.Rarity = card.Rarity
+			existingCard.UpdatedAt = now
 
-			if err := i.cardStore.Update(existingCard); err != nil {
+			if err := i.cardStore.Update(ctx, existingCard); err != nil {
 				result.Errors = append(result.Errors, fmt.Sprintf("Error updating card: %v", err))
 				continue
 			}
 			result.UpdatedCards++
 		} else {
 			// Create new card
-			if err := i.cardStore.Create(card); err != nil {
+			if err := i.cardStore.Create(ctx, card); err != nil {
 				result.Errors = append(result.Errors, fmt.Sprintf("Error creating card: %v", err))
 				continue
 			}
@@ -0,0 +1,667 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/shiftregister-vg/card-craft/internal/cards"
+	"github.com/shiftregister-vg/card-craft/internal/database"
+)
+
+// Status is the state of an import job
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Trigger records what started an import job
+type Trigger string
+
+const (
+	TriggerScheduler Trigger = "scheduler"
+	TriggerCLI       Trigger = "cli"
+	TriggerAPI       Trigger = "api"
+)
+
+const (
+	// maxErrorSamples is the number of non-fatal errors kept on a job
+	maxErrorSamples = 10
+	// flushInterval is how often the counters of a running job are written to the database
+	flushInterval = 5 * time.Second
+)
+
+// ErrImportRunning is returned when an import of the same game is already running, in this
+// process or any other sharing the database
+var ErrImportRunning = errors.New("an import of this game is already running")
+
+// ErrShuttingDown is returned by Start once Shutdown has been called
+var ErrShuttingDown = errors.New("import jobs are shutting down")
+
+// Job is a single run of a card importer
+type Job struct {
+	ID               uuid.UUID  `json:"id"`
+	Game             string     `json:"game"`
+	Trigger          Trigger    `json:"trigger"`
+	Status           Status     `json:"status"`
+	StartedAt        time.Time  `json:"startedAt"`
+	FinishedAt       *time.Time `json:"finishedAt"`
+	CardsProcessed   int        `json:"cardsProcessed"`
+	CardsInserted    int        `json:"cardsInserted"`
+	CardsUpdated     int        `json:"cardsUpdated"`
+	CardsSkipped     int        `json:"cardsSkipped"`
+	BatchesProcessed int        `json:"batchesProcessed"`
+	BatchesTotal     int        `json:"batchesTotal"`
+	ErrorCount       int        `json:"errorCount"`
+	ErrorMessage     *string    `json:"errorMessage"`
+	ErrorSamples     []string   `json:"errorSamples"`
+	CancelRequested  bool       `json:"cancelRequested"`
+	StartedBy        *uuid.UUID `json:"startedBy"` // The user who started it through the API
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+}
+
+// Service records import jobs and their progress, and publishes the progress of the jobs
+// running in this process to subscribers
+type Service struct {
+	db *sql.DB
+
+	mu          sync.Mutex
+	running     map[uuid.UUID]*recorder
+	subscribers map[uuid.UUID][]chan *Job
+
+	// detached tracks the jobs started by Start, which Shutdown waits for and, through
+	// shutdown, cancels
+	detached     sync.WaitGroup
+	closed       bool
+	shutdown     context.Context
+	stopDetached context.CancelFunc
+}
+
+// NewService creates a new import job service
+func NewService(db *sql.DB) *Service {
+	shutdown, stopDetached := context.WithCancel(context.Background())
+	return &Service{
+		db:           db,
+		running:      make(map[uuid.UUID]*recorder),
+		subscribers:  make(map[uuid.UUID][]chan *Job),
+		shutdown:     shutdown,
+		stopDetached: stopDetached,
+	}
+}
+
+// Run records a job for game while fn runs. The context passed to fn carries a progress
+// reporter, so importers that report through cards.ProgressFromContext update the job's counters.
+// The returned error is the one returned by fn, or ErrImportRunning if an import of the game
+// is already running. startedBy is the user who asked for the import, or uuid.Nil.
+func (s *Service) Run(ctx context.Context, game string, trigger Trigger, startedBy uuid.UUID, fn func(ctx context.Context) error) (*Job, error) {
+	lock, err := s.lock(ctx, game)
+	if err != nil {
+		return nil, err
+	}
+	defer s.unlock(lock, game)
+
+	job, err := s.Create(ctx, game, trigger, startedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.execute(ctx, s.track(job), fn)
+}
+
+// Start records a job for game and runs fn in the background, returning the running job.
+// The import is detached from the cancellation of ctx, so it outlives the request that started it,
+// until Shutdown. It returns ErrImportRunning if an import of the game is already running.
+func (s *Service) Start(ctx context.Context, game string, trigger Trigger, startedBy uuid.UUID, fn func(ctx context.Context) error) (*Job, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, ErrShuttingDown
+	}
+	s.detached.Add(1)
+	s.mu.Unlock()
+
+	started := false
+	defer func() {
+		if !started {
+			s.detached.Done()
+		}
+	}()
+
+	lock, err := s.lock(ctx, game)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.Create(ctx, game, trigger, startedBy)
+	if err != nil {
+		s.unlock(lock, game)
+		return nil, err
+	}
+
+	rec := s.track(job)
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stopOnShutdown := context.AfterFunc(s.shutdown, cancel)
+	started = true
+	go func() {
+		defer s.detached.Done()
+		defer s.unlock(lock, game)
+		defer stopOnShutdown()
+		defer cancel()
+		if _, err := s.execute(runCtx, rec, fn); err != nil {
+			log.Printf("Import job %s for %s failed: %v", job.ID, game, err)
+		}
+	}()
+
+	return rec.snapshot(), nil
+}
+
+// Shutdown stops Start from running new jobs and waits for the ones it started to finish.
+// If ctx is done first, those jobs are cancelled, keeping the batches they already committed,
+// and Shutdown returns once they have wound down.
+func (s *Service) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		s.detached.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		log.Printf("Cancelling import jobs still running at shutdown")
+		s.stopDetached()
+		<-drained
+		return ctx.Err()
+	}
+}
+
+// lock takes the advisory lock that keeps two imports of a game from running at once, so
+// they cannot overwrite each other's checkpoint
+func (s *Service) lock(ctx context.Context, game string) (*database.AdvisoryLock, error) {
+	lock, err := database.TryAdvisoryLock(ctx, s.db, "card-import:"+game)
+	if err != nil {
+		return nil, err
+	}
+	if lock == nil {
+		return nil, ErrImportRunning
+	}
+	return lock, nil
+}
+
+// unlock releases the lock taken by lock
+func (s *Service) unlock(lock *database.AdvisoryLock, game string) {
+	if err := lock.Release(); err != nil {
+		log.Printf("Error unlocking %s card import: %v", game, err)
+	}
+}
+
+// track registers a recorder for a job that is about to run in this process
+func (s *Service) track(job *Job) *recorder {
+	rec := &recorder{job: job, service: s}
+
+	s.mu.Lock()
+	s.running[job.ID] = rec
+	s.mu.Unlock()
+
+	return rec
+}
+
+// execute runs fn while recording its progress on the tracked job
+func (s *Service) execute(ctx context.Context, rec *recorder, fn func(ctx context.Context) error) (*Job, error) {
+	jobID := rec.job.ID
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rec.mu.Lock()
+	rec.cancel = cancel
+	cancelRequested := rec.job.CancelRequested
+	rec.mu.Unlock()
+	if cancelRequested {
+		cancel()
+	}
+
+	done := make(chan struct{})
+	flushed := make(chan struct{})
+
+	// Periodically write the counters so running jobs show their progress, and stop the
+	// import if another process asked for it to be cancelled
+	go func() {
+		defer close(flushed)
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				job := rec.snapshot()
+				cancelRequested, err := s.save(ctx, job)
+				if err != nil {
+					log.Printf("Error saving progress of import job %s: %v", jobID, err)
+				}
+				if cancelRequested {
+					log.Printf("Cancelling import job %s as requested", jobID)
+					cancel()
+				}
+				s.publish(job)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	runErr := fn(cards.WithProgressReporter(ctx, rec))
+	close(done)
+	<-flushed
+
+	final := rec.finish(runErr)
+
+	// Record the outcome even when the import was stopped by a cancelled context
+	if _, err := s.save(context.WithoutCancel(ctx), final); err != nil {
+		log.Printf("Error saving result of import job %s: %v", jobID, err)
+	}
+
+	s.mu.Lock()
+	delete(s.running, jobID)
+	s.mu.Unlock()
+	s.publish(final)
+
+	return final, runErr
+}
+
+// Cancel asks a running job to stop. Jobs running in this process are cancelled right away;
+// jobs running elsewhere, such as in the import CLI, stop the next time they save their progress.
+// Batches that were already committed are kept, so an importer that checkpoints can resume later.
+// It returns nil if the job does not exist.
+func (s *Service) Cancel(ctx context.Context, id uuid.UUID) (*Job, error) {
+	query := `
+		UPDATE import_jobs SET cancel_requested = true
+		WHERE id = $1 AND status = $2
+	`
+	if _, err := s.db.ExecContext(ctx, query, id, StatusRunning); err != nil {
+		return nil, fmt.Errorf("failed to cancel import job: %w", err)
+	}
+
+	s.mu.Lock()
+	rec, ok := s.running[id]
+	s.mu.Unlock()
+
+	if ok {
+		rec.requestCancel()
+		return rec.snapshot(), nil
+	}
+
+	return s.FindByID(ctx, id)
+}
+
+// Subscribe returns a channel that receives the job each time its progress changes. The channel
+// is closed once the job has finished or ctx is done. Jobs running in another process, such as
+// the import CLI, are followed by polling the database. It returns nil if the job does not exist.
+func (s *Service) Subscribe(ctx context.Context, id uuid.UUID) (<-chan *Job, error) {
+	job, err := s.FindByID(ctx, id)
+	if err != nil || job == nil {
+		return nil, err
+	}
+
+	updates := make(chan *Job, 1)
+
+	s.mu.Lock()
+	if rec, ok := s.running[id]; ok {
+		updates <- rec.snapshot()
+		s.subscribers[id] = append(s.subscribers[id], updates)
+		s.mu.Unlock()
+
+		go func() {
+			<-ctx.Done()
+			s.unsubscribe(id, updates)
+		}()
+		return updates, nil
+	}
+	s.mu.Unlock()
+
+	go s.poll(ctx, job, updates)
+	return updates, nil
+}
+
+// poll follows a job that is not running in this process until it finishes
+func (s *Service) poll(ctx context.Context, job *Job, updates chan *Job) {
+	defer close(updates)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case updates <- job:
+		case <-ctx.Done():
+			return
+		}
+
+		if job.Status != StatusRunning {
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		latest, err := s.FindByID(ctx, job.ID)
+		if err != nil {
+			log.Printf("Error polling import job %s: %v", job.ID, err)
+			continue
+		}
+		if latest == nil {
+			return
+		}
+		job = latest
+	}
+}
+
+// publish sends the job to its subscribers, replacing any update they have not received yet.
+// Subscribers are closed once the job has finished.
+func (s *Service) publish(job *Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, updates := range s.subscribers[job.ID] {
+		select {
+		case <-updates:
+		default:
+		}
+		updates <- job
+
+		if job.Status != StatusRunning {
+			close(updates)
+		}
+	}
+
+	if job.Status != StatusRunning {
+		delete(s.subscribers, job.ID)
+	}
+}
+
+// unsubscribe removes and closes a subscription that is no longer listened to
+func (s *Service) unsubscribe(id uuid.UUID, updates chan *Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subscribers := s.subscribers[id]
+	for i, ch := range subscribers {
+		if ch == updates {
+			s.subscribers[id] = append(subscribers[:i], subscribers[i+1:]...)
+			close(updates)
+			break
+		}
+	}
+	if len(s.subscribers[id]) == 0 {
+		delete(s.subscribers, id)
+	}
+}
+
+// Create records the start of a new running job. startedBy is the user who asked for it, or
+// uuid.Nil.
+func (s *Service) Create(ctx context.Context, game string, trigger Trigger, startedBy uuid.UUID) (*Job, error) {
+	job := &Job{
+		ID:           uuid.New(),
+		Game:         game,
+		Trigger:      trigger,
+		Status:       StatusRunning,
+		ErrorSamples: []string{},
+	}
+	if startedBy != uuid.Nil {
+		job.StartedBy = &startedBy
+	}
+
+	query := `
+		INSERT INTO import_jobs (id, game, trigger, status, started_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING started_at, created_at, updated_at
+	`
+
+	err := s.db.QueryRowContext(ctx, query, job.ID, job.Game, job.Trigger, job.Status, job.StartedBy).
+		Scan(&job.StartedAt, &job.CreatedAt, &job.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create import job: %w", err)
+	}
+
+	return job, nil
+}
+
+// FindByID finds an import job by its ID
+func (s *Service) FindByID(ctx context.Context, id uuid.UUID) (*Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM import_jobs
+		WHERE id = $1
+	`
+
+	job, err := scanJob(s.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find import job: %w", err)
+	}
+
+	return job, nil
+}
+
+// List returns the most recent import jobs, optionally limited to one game
+func (s *Service) List(ctx context.Context, game string, limit int) ([]*Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM import_jobs
+		WHERE ($1 = '' OR game = $1)
+		ORDER BY started_at DESC
+		LIMIT $2
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, game, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list import jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan import job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+// save writes the status and counters of a job and reports whether its cancellation was requested
+func (s *Service) save(ctx context.Context, job *Job) (bool, error) {
+	query := `
+		UPDATE import_jobs SET
+			status = $2,
+			finished_at = $3,
+			cards_processed = $4,
+			cards_inserted = $5,
+			cards_updated = $6,
+			cards_skipped = $7,
+			error_count = $8,
+			error_message = $9,
+			error_samples = $10,
+			batches_processed = $11,
+			batches_total = $12
+		WHERE id = $1
+		RETURNING cancel_requested
+	`
+
+	var cancelRequested bool
+	err := s.db.QueryRowContext(ctx, query,
+		job.ID,
+		job.Status,
+		job.FinishedAt,
+		job.CardsProcessed,
+		job.CardsInserted,
+		job.CardsUpdated,
+		job.CardsSkipped,
+		job.ErrorCount,
+		job.ErrorMessage,
+		pq.Array(job.ErrorSamples),
+		job.BatchesProcessed,
+		job.BatchesTotal,
+	).Scan(&cancelRequested)
+	if err != nil {
+		return false, fmt.Errorf("failed to update import job: %w", err)
+	}
+
+	return cancelRequested, nil
+}
+
+const jobColumns = `id, game, trigger, status, started_at, finished_at, cards_processed,
+	cards_inserted, cards_updated, cards_skipped, error_count, error_message, error_samples,
+	batches_processed, batches_total, cancel_requested, started_by, created_at, updated_at`
+
+// scanJob scans a row selected with jobColumns
+func scanJob(row interface{ Scan(...any) error }) (*Job, error) {
+	var job Job
+	var finishedAt sql.NullTime
+	var errorMessage sql.NullString
+	var startedBy uuid.NullUUID
+
+	err := row.Scan(
+		&job.ID,
+		&job.Game,
+		&job.Trigger,
+		&job.Status,
+		&job.StartedAt,
+		&finishedAt,
+		&job.CardsProcessed,
+		&job.CardsInserted,
+		&job.CardsUpdated,
+		&job.CardsSkipped,
+		&job.ErrorCount,
+		&errorMessage,
+		pq.Array(&job.ErrorSamples),
+		&job.BatchesProcessed,
+		&job.BatchesTotal,
+		&job.CancelRequested,
+		&startedBy,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+	if errorMessage.Valid {
+		job.ErrorMessage = &errorMessage.String
+	}
+	if startedBy.Valid {
+		job.StartedBy = &startedBy.UUID
+	}
+	if job.ErrorSamples == nil {
+		job.ErrorSamples = []string{}
+	}
+
+	return &job, nil
+}
+
+// recorder collects the progress reported by a running import
+type recorder struct {
+	mu      sync.Mutex
+	job     *Job
+	service *Service
+	cancel  context.CancelFunc
+}
+
+// AddCards implements the cards.ProgressReporter interface
+func (r *recorder) AddCards(inserted, updated, skipped int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.job.CardsInserted += inserted
+	r.job.CardsUpdated += updated
+	r.job.CardsSkipped += skipped
+	r.job.CardsProcessed += inserted + updated + skipped
+}
+
+// SetBatches implements the cards.ProgressReporter interface
+func (r *recorder) SetBatches(processed, total int) {
+	r.mu.Lock()
+	r.job.BatchesProcessed = processed
+	r.job.BatchesTotal = total
+	r.mu.Unlock()
+
+	r.service.publish(r.snapshot())
+}
+
+// RecordError implements the cards.ProgressReporter interface
+func (r *recorder) RecordError(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.job.ErrorCount++
+	if len(r.job.ErrorSamples) < maxErrorSamples {
+		r.job.ErrorSamples = append(r.job.ErrorSamples, err.Error())
+	}
+}
+
+// requestCancel cancels the context of the running import
+func (r *recorder) requestCancel() {
+	r.mu.Lock()
+	r.job.CancelRequested = true
+	cancel := r.cancel
+	r.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// snapshot returns a copy of the job as it currently stands
+func (r *recorder) snapshot() *Job {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job := *r.job
+	job.ErrorSamples = append([]string{}, r.job.ErrorSamples...)
+	return &job
+}
+
+// finish marks the job as finished with the outcome of the import and returns a copy of it
+func (r *recorder) finish(err error) *Job {
+	r.mu.Lock()
+	now := time.Now()
+	r.job.FinishedAt = &now
+	switch {
+	case err == nil:
+		r.job.Status = StatusSucceeded
+	case errors.Is(err, context.Canceled):
+		r.job.Status = StatusCancelled
+	default:
+		message := err.Error()
+		r.job.Status = StatusFailed
+		r.job.ErrorMessage = &message
+	}
+	r.mu.Unlock()
+
+	return r.snapshot()
+}
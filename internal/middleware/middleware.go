@@ -3,17 +3,21 @@ package middleware
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/shiftregister-vg/card-craft/internal/auth"
 	"github.com/shiftregister-vg/card-craft/internal/models"
 	"github.com/ulule/limiter/v3"
 	"github.com/ulule/limiter/v3/drivers/store/memory"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
 )
 
 type AuthMiddleware struct {
@@ -22,8 +26,18 @@ type AuthMiddleware struct {
 }
 
 type graphqlRequest struct {
-	Query     string                 `json:"query"`
-	Variables map[string]interface{} `json:"variables"`
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// anonymousFields are the top-level GraphQL fields that can be used without signing in
+var anonymousFields = map[string]bool{
+	"login":       true,
+	"register":    true,
+	"publicDeck":  true,
+	"publicDecks": true,
+	"__typename":  true,
 }
 
 func NewAuthMiddleware(jwtSecret string, userStore *models.UserStore) *AuthMiddleware {
@@ -47,69 +61,108 @@ func (m *AuthMiddleware) isAuthenticationRequired(r *http.Request) bool {
 		return true
 	}
 
-	// Allow unauthenticated access to login and register mutations
-	if strings.Contains(req.Query, "mutation Login") || strings.Contains(req.Query, "mutation Register") {
+	// Allow unauthenticated access to operations that only select anonymous fields, such as
+	// the login and register mutations and the public deck queries
+	return !isAnonymousOperation(req)
+}
+
+// isAnonymousOperation reports whether every top-level field the request's operation selects
+// can be used without signing in. Requests that cannot be parsed require authentication.
+func isAnonymousOperation(req graphqlRequest) bool {
+	doc, err := parser.ParseQuery(&ast.Source{Input: req.Query})
+	if err != nil {
+		return false
+	}
+
+	op := doc.Operations.ForName(req.OperationName)
+	if op == nil || len(op.SelectionSet) == 0 {
 		return false
 	}
 
+	for _, selection := range op.SelectionSet {
+		field, ok := selection.(*ast.Field)
+		if !ok || !anonymousFields[field.Name] {
+			return false
+		}
+	}
+
 	return true
 }
 
 func (m *AuthMiddleware) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !m.isAuthenticationRequired(r) {
+			// Signed-in users keep their identity on anonymous operations, so owners can
+			// still see their own unlisted and private decks
+			if user, err := m.authenticate(r.Header.Get("Authorization")); err == nil {
+				r = r.WithContext(context.WithValue(r.Context(), auth.UserContextKey, user))
+			}
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		user, err := m.authenticate(r.Header.Get("Authorization"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
 			return
 		}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			http.Error(w, "Invalid authorization header", http.StatusUnauthorized)
-			return
-		}
+		ctx := context.WithValue(r.Context(), auth.UserContextKey, user)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
 
-		token, err := jwt.Parse(parts[1], func(token *jwt.Token) (interface{}, error) {
-			return []byte(m.jwtSecret), nil
-		})
+// WebsocketInit authenticates a GraphQL websocket connection using the Authorization value of
+// its connection_init payload, since browsers cannot set headers on websocket requests
+func (m *AuthMiddleware) WebsocketInit(ctx context.Context, initPayload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+	user, err := m.authenticate(initPayload.Authorization())
+	if err != nil {
+		return ctx, nil, err
+	}
 
-		if err != nil || !token.Valid {
-			http.Error(w, "Invalid token", http.StatusUnauthorized)
-			return
-		}
+	return context.WithValue(ctx, auth.UserContextKey, user), nil, nil
+}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			http.Error(w, "Invalid token claims", http.StatusUnauthorized)
-			return
-		}
+// authenticate resolves the user of a "Bearer <token>" authorization value
+func (m *AuthMiddleware) authenticate(authHeader string) (*models.User, error) {
+	if authHeader == "" {
+		return nil, errors.New("Unauthorized")
+	}
 
-		userID, ok := claims["sub"].(string)
-		if !ok {
-			http.Error(w, "Invalid token claims", http.StatusUnauthorized)
-			return
-		}
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return nil, errors.New("Invalid authorization header")
+	}
 
-		uuid, err := uuid.Parse(userID)
-		if err != nil {
-			http.Error(w, "Invalid user ID", http.StatusUnauthorized)
-			return
-		}
+	token, err := jwt.Parse(parts[1], func(token *jwt.Token) (interface{}, error) {
+		return []byte(m.jwtSecret), nil
+	})
 
-		user, err := m.userStore.FindByID(uuid)
-		if err != nil || user == nil {
-			http.Error(w, "User not found", http.StatusUnauthorized)
-			return
-		}
+	if err != nil || !token.Valid {
+		return nil, errors.New("Invalid token")
+	}
 
-		ctx := context.WithValue(r.Context(), auth.UserContextKey, user)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("Invalid token claims")
+	}
+
+	userID, ok := claims["sub"].(string)
+	if !ok {
+		return nil, errors.New("Invalid token claims")
+	}
+
+	uuid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("Invalid user ID")
+	}
+
+	user, err := m.userStore.FindByID(uuid)
+	if err != nil || user == nil {
+		return nil, errors.New("User not found")
+	}
+
+	return user, nil
 }
 
 type RateLimitMiddleware struct {
@@ -2,7 +2,12 @@ package models
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,20 +20,137 @@ type Deck struct {
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
 	Game        string    `json:"game"`
-	IsPublic    bool      `json:"isPublic"`
+	Visibility  string    `json:"visibility"`
+	Slug        string    `json:"slug"`
 	CreatedAt   time.Time `json:"createdAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
 }
 
+// DeckConnection represents a paginated connection of decks
+type DeckConnection struct {
+	Edges    []*DeckEdge `json:"edges"`
+	PageInfo *PageInfo   `json:"pageInfo"`
+}
+
+// DeckEdge represents an edge in a deck connection
+type DeckEdge struct {
+	Node   *Deck  `json:"node"`
+	Cursor string `json:"cursor"`
+}
+
+// Deck visibilities. Unlisted decks can be opened by anyone with their link but are not listed
+// with the public decks.
+const (
+	VisibilityPrivate  = "private"
+	VisibilityUnlisted = "unlisted"
+	VisibilityPublic   = "public"
+)
+
+// IsValidVisibility reports whether a value is one of the deck visibilities
+func IsValidVisibility(visibility string) bool {
+	switch visibility {
+	case VisibilityPrivate, VisibilityUnlisted, VisibilityPublic:
+		return true
+	}
+	return false
+}
+
+// IsShared reports whether users other than the owner may read the deck
+func (d *Deck) IsShared() bool {
+	return d.Visibility == VisibilityPublic || d.Visibility == VisibilityUnlisted
+}
+
+// Deck zones a card can be placed in
+const (
+	ZoneMain       = "main"
+	ZoneSideboard  = "sideboard"
+	ZoneCommander  = "commander"
+	ZoneCompanion  = "companion"
+	ZoneMaybeboard = "maybeboard"
+	ZoneLeader     = "leader"
+	ZoneBase       = "base"
+)
+
+// gameZones lists the zones the decks of each game may use, starting with the main deck
+var gameZones = map[string][]string{
+	"mtg":      {ZoneMain, ZoneSideboard, ZoneCommander, ZoneCompanion, ZoneMaybeboard},
+	"pokemon":  {ZoneMain},
+	"lorcana":  {ZoneMain},
+	"starwars": {ZoneLeader, ZoneBase, ZoneMain, ZoneSideboard},
+}
+
+// ZonesForGame returns the zones the decks of a game may use. Games without zone rules
+// only have a main deck.
+func ZonesForGame(game string) []string {
+	if zones, ok := gameZones[strings.ToLower(game)]; ok {
+		return zones
+	}
+	return []string{ZoneMain}
+}
+
+// IsValidZone reports whether the decks of a game may use a zone
+func IsValidZone(game, zone string) bool {
+	for _, z := range ZonesForGame(game) {
+		if z == zone {
+			return true
+		}
+	}
+	return false
+}
+
 type DeckCard struct {
 	ID        uuid.UUID `json:"id"`
 	DeckID    uuid.UUID `json:"deckId"`
 	CardID    uuid.UUID `json:"cardId"`
 	Quantity  int       `json:"quantity"`
+	Zone      string    `json:"zone"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
+// NewDeckSlug builds a share link slug from a deck name and a random suffix, so decks with the
+// same name get different slugs
+func NewDeckSlug(name string) string {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			if b.Len() >= maxSlugNameLength {
+				break
+			}
+		} else {
+			dash = true
+		}
+	}
+
+	if b.Len() > 0 {
+		b.WriteByte('-')
+	}
+	b.WriteString(hex.EncodeToString(suffix))
+	return b.String()
+}
+
+// maxSlugNameLength caps the part of a slug taken from the deck name
+const maxSlugNameLength = 60
+
+// setDeckDefaults fills in the visibility and slug of a deck about to be created
+func setDeckDefaults(deck *Deck) {
+	if deck.Visibility == "" {
+		deck.Visibility = VisibilityPrivate
+	}
+	if deck.Slug == "" {
+		deck.Slug = NewDeckSlug(deck.Name)
+	}
+}
+
 type DeckStore struct {
 	db *sql.DB
 }
@@ -37,41 +159,55 @@ func NewDeckStore(db *sql.DB) *DeckStore {
 	return &DeckStore{db: db}
 }
 
-func (s *DeckStore) Create(deck *Deck) error {
+// Create creates an empty deck and records it as the deck's first revision
+func (s *DeckStore) Create(ctx context.Context, deck *Deck) error {
+	return s.CreateWithCards(ctx, deck, nil, "Created deck")
+}
+
+func (s *DeckStore) FindByID(id uuid.UUID) (*Deck, error) {
+	deck := &Deck{}
 	query := `
-		INSERT INTO decks (id, user_id, name, description, game, is_public)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING created_at, updated_at
+		SELECT id, user_id, name, description, game, visibility, slug, created_at, updated_at
+		FROM decks
+		WHERE id = $1
 	`
 
-	err := s.db.QueryRow(
-		query,
-		deck.ID,
-		deck.UserID,
-		deck.Name,
-		deck.Description,
-		deck.Game,
-		deck.IsPublic,
-	).Scan(&deck.CreatedAt, &deck.UpdatedAt)
+	err := s.db.QueryRow(query, id).Scan(
+		&deck.ID,
+		&deck.UserID,
+		&deck.Name,
+		&deck.Description,
+		&deck.Game,
+		&deck.Visibility,
+		&deck.Slug,
+		&deck.CreatedAt,
+		&deck.UpdatedAt,
+	)
 
-	return err
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return deck, err
 }
 
-func (s *DeckStore) FindByID(id uuid.UUID) (*Deck, error) {
+// FindBySlug returns the deck with a share link slug, or nil if there is none
+func (s *DeckStore) FindBySlug(slug string) (*Deck, error) {
 	deck := &Deck{}
 	query := `
-		SELECT id, user_id, name, description, game, is_public, created_at, updated_at
+		SELECT id, user_id, name, description, game, visibility, slug, created_at, updated_at
 		FROM decks
-		WHERE id = $1
+		WHERE slug = $1
 	`
 
-	err := s.db.QueryRow(query, id).Scan(
+	err := s.db.QueryRow(query, slug).Scan(
 		&deck.ID,
 		&deck.UserID,
 		&deck.Name,
 		&deck.Description,
 		&deck.Game,
-		&deck.IsPublic,
+		&deck.Visibility,
+		&deck.Slug,
 		&deck.CreatedAt,
 		&deck.UpdatedAt,
 	)
@@ -83,9 +219,117 @@ func (s *DeckStore) FindByID(id uuid.UUID) (*Deck, error) {
 	return deck, err
 }
 
+// PublicDeckFilter narrows the public decks listed by FindPublic
+type PublicDeckFilter struct {
+	// Game limits the decks to one game when set
+	Game string
+	// Query matches the deck name or description, ignoring case
+	Query string
+	// CardID limits the decks to those containing a card
+	CardID *uuid.UUID
+}
+
+// FindPublic lists public decks, most recently updated first. after is the cursor returned by
+// the previous page; the returned cursor is empty when there are no more decks.
+func (s *DeckStore) FindPublic(filter PublicDeckFilter, first int, after string) ([]*Deck, string, error) {
+	var afterTime time.Time
+	var afterID uuid.UUID
+	if after != "" {
+		var err error
+		if afterTime, afterID, err = decodeDeckCursor(after); err != nil {
+			return nil, "", err
+		}
+	}
+
+	query := `
+		SELECT id, user_id, name, description, game, visibility, slug, created_at, updated_at
+		FROM decks d
+		WHERE visibility = $1
+		AND ($2 = '' OR LOWER(game) = LOWER($2))
+		AND ($3 = '' OR name ILIKE '%' || $3 || '%' OR description ILIKE '%' || $3 || '%')
+		AND ($4::uuid IS NULL OR EXISTS (
+			SELECT 1 FROM deck_cards dc WHERE dc.deck_id = d.id AND dc.card_id = $4
+		))
+		AND ($5 = '' OR (updated_at, id) < ($6, $7))
+		ORDER BY updated_at DESC, id DESC
+		LIMIT $8
+	`
+
+	// Fetch one extra deck to learn whether another page follows
+	rows, err := s.db.Query(query, VisibilityPublic, filter.Game, filter.Query, filter.CardID,
+		after, afterTime, afterID, first+1)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var decks []*Deck
+	for rows.Next() {
+		deck := &Deck{}
+		err := rows.Scan(
+			&deck.ID,
+			&deck.UserID,
+			&deck.Name,
+			&deck.Description,
+			&deck.Game,
+			&deck.Visibility,
+			&deck.Slug,
+			&deck.CreatedAt,
+			&deck.UpdatedAt,
+		)
+		if err != nil {
+			return nil, "", err
+		}
+		decks = append(decks, deck)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(decks) <= first {
+		return decks, "", nil
+	}
+
+	decks = decks[:first]
+	return decks, DeckCursor(decks[first-1]), nil
+}
+
+// ErrInvalidCursor is returned when a pagination cursor was not created by DeckCursor
+var ErrInvalidCursor = errors.New("invalid cursor format")
+
+// DeckCursor returns the pagination cursor that points just past a deck in FindPublic
+func DeckCursor(deck *Deck) string {
+	raw := deck.UpdatedAt.UTC().Format(time.RFC3339Nano) + "|" + deck.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeDeckCursor reads a cursor created by DeckCursor
+func decodeDeckCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+
+	updatedAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, updatedAt)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	deckID, err := uuid.Parse(id)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+
+	return t, deckID, nil
+}
+
 func (s *DeckStore) FindByUserID(userID uuid.UUID) ([]*Deck, error) {
 	query := `
-		SELECT id, user_id, name, description, game, is_public, created_at, updated_at
+		SELECT id, user_id, name, description, game, visibility, slug, created_at, updated_at
 		FROM decks
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -106,7 +350,8 @@ func (s *DeckStore) FindByUserID(userID uuid.UUID) ([]*Deck, error) {
 			&deck.Name,
 			&deck.Description,
 			&deck.Game,
-			&deck.IsPublic,
+			&deck.Visibility,
+			&deck.Slug,
 			&deck.CreatedAt,
 			&deck.UpdatedAt,
 		)
@@ -116,13 +361,13 @@ func (s *DeckStore) FindByUserID(userID uuid.UUID) ([]*Deck, error) {
 		decks = append(decks, deck)
 	}
 
-	return decks, nil
+	return decks, rows.Err()
 }
 
 func (s *DeckStore) Update(deck *Deck) error {
 	query := `
 		UPDATE decks
-		SET name = $1, description = $2, game = $3, is_public = $4
+		SET name = $1, description = $2, game = $3, visibility = $4
 		WHERE id = $5
 		RETURNING updated_at
 	`
@@ -132,7 +377,7 @@ func (s *DeckStore) Update(deck *Deck) error {
 		deck.Name,
 		deck.Description,
 		deck.Game,
-		deck.IsPublic,
+		deck.Visibility,
 		deck.ID,
 	).Scan(&deck.UpdatedAt)
 }
@@ -143,31 +388,62 @@ func (s *DeckStore) Delete(id uuid.UUID) error {
 	return err
 }
 
-func (s *DeckStore) AddCard(deckID, cardID uuid.UUID, quantity int) error {
-	query := `
-		INSERT INTO deck_cards (deck_id, card_id, quantity)
-		VALUES ($1, $2, $3)
-		ON CONFLICT (deck_id, card_id) DO UPDATE
-		SET quantity = deck_cards.quantity + $3
-		RETURNING id, created_at, updated_at
-	`
+// AddCard adds a card to a zone of a deck, increasing the quantity if the card is already in
+// that zone, and records the change as a revision
+func (s *DeckStore) AddCard(ctx context.Context, deckID, cardID uuid.UUID, quantity int, zone, message string) (*DeckCard, error) {
+	deckCard := &DeckCard{
+		DeckID: deckID,
+		CardID: cardID,
+		Zone:   zone,
+	}
+	err := database.WithTransaction(ctx, s.db, func(tx *database.Transaction) error {
+		query := `
+			INSERT INTO deck_cards (deck_id, card_id, quantity, zone)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (deck_id, card_id, zone) DO UPDATE
+			SET quantity = deck_cards.quantity + $3
+			RETURNING id, quantity, created_at, updated_at
+		`
 
-	var id uuid.UUID
-	var createdAt, updatedAt time.Time
-	return s.db.QueryRow(query, deckID, cardID, quantity).Scan(&id, &createdAt, &updatedAt)
+		err := tx.QueryRow(query, deckID, cardID, quantity, deckCard.Zone).Scan(
+			&deckCard.ID,
+			&deckCard.Quantity,
+			&deckCard.CreatedAt,
+			&deckCard.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = snapshotDeck(tx, deckID, message)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deckCard, nil
 }
 
-func (s *DeckStore) RemoveCard(deckID, cardID uuid.UUID) error {
-	query := `DELETE FROM deck_cards WHERE deck_id = $1 AND card_id = $2`
-	_, err := s.db.Exec(query, deckID, cardID)
-	return err
+// RemoveCard removes a card from one zone of a deck and records the change as a revision
+func (s *DeckStore) RemoveCard(ctx context.Context, deckID, cardID uuid.UUID, zone, message string) error {
+	return database.WithTransaction(ctx, s.db, func(tx *database.Transaction) error {
+		query := `DELETE FROM deck_cards WHERE deck_id = $1 AND card_id = $2 AND zone = $3`
+		if _, err := tx.Exec(query, deckID, cardID, zone); err != nil {
+			return err
+		}
+
+		_, err := snapshotDeck(tx, deckID, message)
+		return err
+	})
 }
 
 func (s *DeckStore) GetCards(deckID uuid.UUID) ([]*DeckCard, error) {
 	query := `
-		SELECT id, deck_id, card_id, quantity, created_at, updated_at
+		SELECT id, deck_id, card_id, quantity, zone, created_at, updated_at
 		FROM deck_cards
 		WHERE deck_id = $1
+		ORDER BY created_at
 	`
 
 	rows, err := s.db.Query(query, deckID)
@@ -184,6 +460,7 @@ func (s *DeckStore) GetCards(deckID uuid.UUID) ([]*DeckCard, error) {
 			&deckCard.DeckID,
 			&deckCard.CardID,
 			&deckCard.Quantity,
+			&deckCard.Zone,
 			&deckCard.CreatedAt,
 			&deckCard.UpdatedAt,
 		)
@@ -193,15 +470,17 @@ func (s *DeckStore) GetCards(deckID uuid.UUID) ([]*DeckCard, error) {
 		deckCards = append(deckCards, deckCard)
 	}
 
-	return deckCards, nil
+	return deckCards, rows.Err()
 }
 
-func (s *DeckStore) CreateWithCards(ctx context.Context, deck *Deck, cards []*DeckCard) error {
+// CreateWithCards creates a deck together with its cards and records them as the deck's first revision
+func (s *DeckStore) CreateWithCards(ctx context.Context, deck *Deck, cards []*DeckCard, message string) error {
+	setDeckDefaults(deck)
 	return database.WithTransaction(ctx, s.db, func(tx *database.Transaction) error {
 		// Create the deck
 		query := `
-			INSERT INTO decks (id, user_id, name, description, game, is_public)
-			VALUES ($1, $2, $3, $4, $5, $6)
+			INSERT INTO decks (id, user_id, name, description, game, visibility, slug)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
 			RETURNING created_at, updated_at
 		`
 
@@ -212,7 +491,8 @@ func (s *DeckStore) CreateWithCards(ctx context.Context, deck *Deck, cards []*De
 			deck.Name,
 			deck.Description,
 			deck.Game,
-			deck.IsPublic,
+			deck.Visibility,
+			deck.Slug,
 		).Scan(&deck.CreatedAt, &deck.UpdatedAt)
 		if err != nil {
 			return err
@@ -221,32 +501,39 @@ func (s *DeckStore) CreateWithCards(ctx context.Context, deck *Deck, cards []*De
 		// Add cards to the deck
 		for _, card := range cards {
 			query := `
-				INSERT INTO deck_cards (deck_id, card_id, quantity)
-				VALUES ($1, $2, $3)
-				RETURNING created_at, updated_at
+				INSERT INTO deck_cards (deck_id, card_id, quantity, zone)
+				VALUES ($1, $2, $3, $4)
+				RETURNING id, created_at, updated_at
 			`
 
+			card.DeckID = deck.ID
+			if card.Zone == "" {
+				card.Zone = ZoneMain
+			}
 			err := tx.QueryRow(
 				query,
 				deck.ID,
 				card.CardID,
 				card.Quantity,
-			).Scan(&card.CreatedAt, &card.UpdatedAt)
+				card.Zone,
+			).Scan(&card.ID, &card.CreatedAt, &card.UpdatedAt)
 			if err != nil {
 				return err
 			}
 		}
 
-		return nil
+		_, err = snapshotDeck(tx, deck.ID, message)
+		return err
 	})
 }
 
-func (s *DeckStore) UpdateWithCards(ctx context.Context, deck *Deck, cards []*DeckCard) error {
+// UpdateWithCards replaces a deck and its cards, recording the new card list as a revision
+func (s *DeckStore) UpdateWithCards(ctx context.Context, deck *Deck, cards []*DeckCard, message string) error {
 	return database.WithTransaction(ctx, s.db, func(tx *database.Transaction) error {
 		// Update the deck
 		query := `
 			UPDATE decks
-			SET name = $1, description = $2, game = $3, is_public = $4
+			SET name = $1, description = $2, game = $3, visibility = $4
 			WHERE id = $5
 			RETURNING updated_at
 		`
@@ -256,7 +543,7 @@ func (s *DeckStore) UpdateWithCards(ctx context.Context, deck *Deck, cards []*De
 			deck.Name,
 			deck.Description,
 			deck.Game,
-			deck.IsPublic,
+			deck.Visibility,
 			deck.ID,
 		).Scan(&deck.UpdatedAt)
 		if err != nil {
@@ -272,23 +559,29 @@ func (s *DeckStore) UpdateWithCards(ctx context.Context, deck *Deck, cards []*De
 		// Add new cards
 		for _, card := range cards {
 			query := `
-				INSERT INTO deck_cards (deck_id, card_id, quantity)
-				VALUES ($1, $2, $3)
-				RETURNING created_at, updated_at
+				INSERT INTO deck_cards (deck_id, card_id, quantity, zone)
+				VALUES ($1, $2, $3, $4)
+				RETURNING id, created_at, updated_at
 			`
 
+			card.DeckID = deck.ID
+			if card.Zone == "" {
+				card.Zone = ZoneMain
+			}
 			err := tx.QueryRow(
 				query,
 				deck.ID,
 				card.CardID,
 				card.Quantity,
-			).Scan(&card.CreatedAt, &card.UpdatedAt)
+				card.Zone,
+			).Scan(&card.ID, &card.CreatedAt, &card.UpdatedAt)
 			if err != nil {
 				return err
 			}
 		}
 
-		return nil
+		_, err = snapshotDeck(tx, deck.ID, message)
+		return err
 	})
 }
 
@@ -304,7 +597,7 @@ func (s *DeckStore) DeleteWithCards(ctx context.Context, id uuid.UUID) error {
 func (s *DeckStore) GetDeckCard(id uuid.UUID) (*DeckCard, error) {
 	deckCard := &DeckCard{}
 	query := `
-		SELECT id, deck_id, card_id, quantity, created_at, updated_at
+		SELECT id, deck_id, card_id, quantity, zone, created_at, updated_at
 		FROM deck_cards
 		WHERE id = $1
 	`
@@ -314,6 +607,7 @@ func (s *DeckStore) GetDeckCard(id uuid.UUID) (*DeckCard, error) {
 		&deckCard.DeckID,
 		&deckCard.CardID,
 		&deckCard.Quantity,
+		&deckCard.Zone,
 		&deckCard.CreatedAt,
 		&deckCard.UpdatedAt,
 	)
@@ -325,14 +619,19 @@ func (s *DeckStore) GetDeckCard(id uuid.UUID) (*DeckCard, error) {
 	return deckCard, err
 }
 
-// UpdateDeckCard updates the quantity of a card in a deck
-func (s *DeckStore) UpdateDeckCard(id uuid.UUID, quantity int) error {
-	query := `
-		UPDATE deck_cards
-		SET quantity = $1, updated_at = NOW()
-		WHERE id = $2
-	`
+// UpdateDeckCard updates the quantity and zone of a card in a deck and records the change as a revision
+func (s *DeckStore) UpdateDeckCard(ctx context.Context, deckCard *DeckCard, quantity int, zone, message string) error {
+	return database.WithTransaction(ctx, s.db, func(tx *database.Transaction) error {
+		query := `
+			UPDATE deck_cards
+			SET quantity = $1, zone = $2, updated_at = NOW()
+			WHERE id = $3
+		`
+		if _, err := tx.Exec(query, quantity, zone, deckCard.ID); err != nil {
+			return err
+		}
 
-	_, err := s.db.Exec(query, quantity, id)
-	return err
+		_, err := snapshotDeck(tx, deckCard.DeckID, message)
+		return err
+	})
 }
@@ -0,0 +1,283 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shiftregister-vg/card-craft/internal/database"
+)
+
+// DeckRevision is an immutable snapshot of a deck's card list. Revisions are numbered from 1
+// in the order they were saved.
+type DeckRevision struct {
+	ID        uuid.UUID `json:"id"`
+	DeckID    uuid.UUID `json:"deckId"`
+	Number    int       `json:"number"`
+	Message   string    `json:"message"`
+	CardCount int       `json:"cardCount"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// DeckRevisionCard is a card as it was recorded in a deck revision
+type DeckRevisionCard struct {
+	RevisionID uuid.UUID `json:"revisionId"`
+	CardID     uuid.UUID `json:"cardId"`
+	Quantity   int       `json:"quantity"`
+	Zone       string    `json:"zone"`
+}
+
+// DeckDiff lists the card changes between two versions of a deck
+type DeckDiff struct {
+	FromRevisionID uuid.UUID `json:"fromRevisionId"`
+	// ToRevisionID is nil when the diff is against the deck's current cards
+	ToRevisionID *uuid.UUID        `json:"toRevisionId"`
+	Added        []*DeckCardChange `json:"added"`
+	Removed      []*DeckCardChange `json:"removed"`
+	Changed      []*DeckCardChange `json:"changed"`
+}
+
+// DeckCardChange is the difference in quantity of a card in one zone of a deck
+type DeckCardChange struct {
+	CardID       uuid.UUID `json:"cardId"`
+	Zone         string    `json:"zone"`
+	FromQuantity int       `json:"fromQuantity"`
+	ToQuantity   int       `json:"toQuantity"`
+}
+
+// CreateRevision snapshots the current card list of a deck
+func (s *DeckStore) CreateRevision(ctx context.Context, deckID uuid.UUID, message string) (*DeckRevision, error) {
+	var revision *DeckRevision
+	err := database.WithTransaction(ctx, s.db, func(tx *database.Transaction) error {
+		var err error
+		revision, err = snapshotDeck(tx, deckID, message)
+		return err
+	})
+	return revision, err
+}
+
+// snapshotDeck records the deck's current cards as its next revision. The deck row is locked
+// so concurrent saves are numbered one after the other.
+func snapshotDeck(tx *database.Transaction, deckID uuid.UUID, message string) (*DeckRevision, error) {
+	if _, err := tx.Exec(`SELECT id FROM decks WHERE id = $1 FOR UPDATE`, deckID); err != nil {
+		return nil, err
+	}
+
+	revision := &DeckRevision{DeckID: deckID, Message: message}
+	query := `
+		INSERT INTO deck_revisions (deck_id, number, message)
+		SELECT $1, COALESCE(MAX(number), 0) + 1, $2
+		FROM deck_revisions
+		WHERE deck_id = $1
+		RETURNING id, number, created_at
+	`
+	err := tx.QueryRow(query, deckID, message).Scan(&revision.ID, &revision.Number, &revision.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `
+		INSERT INTO deck_revision_cards (revision_id, card_id, quantity, zone)
+		SELECT $1, card_id, quantity, zone
+		FROM deck_cards
+		WHERE deck_id = $2
+	`
+	if _, err := tx.Exec(query, revision.ID, deckID); err != nil {
+		return nil, err
+	}
+
+	query = `SELECT COALESCE(SUM(quantity), 0) FROM deck_revision_cards WHERE revision_id = $1`
+	if err := tx.QueryRow(query, revision.ID).Scan(&revision.CardCount); err != nil {
+		return nil, err
+	}
+
+	return revision, nil
+}
+
+// ListRevisions returns the revisions of a deck, newest first
+func (s *DeckStore) ListRevisions(deckID uuid.UUID) ([]*DeckRevision, error) {
+	query := `
+		SELECT r.id, r.deck_id, r.number, r.message,
+			(SELECT COALESCE(SUM(quantity), 0) FROM deck_revision_cards WHERE revision_id = r.id),
+			r.created_at
+		FROM deck_revisions r
+		WHERE r.deck_id = $1
+		ORDER BY r.number DESC
+	`
+
+	rows, err := s.db.Query(query, deckID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*DeckRevision
+	for rows.Next() {
+		revision := &DeckRevision{}
+		err := rows.Scan(
+			&revision.ID,
+			&revision.DeckID,
+			&revision.Number,
+			&revision.Message,
+			&revision.CardCount,
+			&revision.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, rows.Err()
+}
+
+// FindRevision returns a deck revision by its ID, or nil if it does not exist
+func (s *DeckStore) FindRevision(id uuid.UUID) (*DeckRevision, error) {
+	revision := &DeckRevision{}
+	query := `
+		SELECT r.id, r.deck_id, r.number, r.message,
+			(SELECT COALESCE(SUM(quantity), 0) FROM deck_revision_cards WHERE revision_id = r.id),
+			r.created_at
+		FROM deck_revisions r
+		WHERE r.id = $1
+	`
+
+	err := s.db.QueryRow(query, id).Scan(
+		&revision.ID,
+		&revision.DeckID,
+		&revision.Number,
+		&revision.Message,
+		&revision.CardCount,
+		&revision.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return revision, err
+}
+
+// GetRevisionCards returns the cards recorded in a deck revision
+func (s *DeckStore) GetRevisionCards(revisionID uuid.UUID) ([]*DeckRevisionCard, error) {
+	query := `
+		SELECT revision_id, card_id, quantity, zone
+		FROM deck_revision_cards
+		WHERE revision_id = $1
+		ORDER BY zone, card_id
+	`
+
+	rows, err := s.db.Query(query, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cards []*DeckRevisionCard
+	for rows.Next() {
+		card := &DeckRevisionCard{}
+		if err := rows.Scan(&card.RevisionID, &card.CardID, &card.Quantity, &card.Zone); err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+
+	return cards, rows.Err()
+}
+
+// RestoreRevision replaces the cards of a deck with those of one of its revisions. The restore
+// is recorded as a new revision, so the history is never rewritten.
+func (s *DeckStore) RestoreRevision(ctx context.Context, deck *Deck, revision *DeckRevision, message string) (*DeckRevision, error) {
+	var restored *DeckRevision
+	err := database.WithTransaction(ctx, s.db, func(tx *database.Transaction) error {
+		err := tx.QueryRow(
+			`UPDATE decks SET updated_at = NOW() WHERE id = $1 RETURNING updated_at`,
+			deck.ID,
+		).Scan(&deck.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("DELETE FROM deck_cards WHERE deck_id = $1", deck.ID); err != nil {
+			return err
+		}
+
+		query := `
+			INSERT INTO deck_cards (deck_id, card_id, quantity, zone)
+			SELECT $1, card_id, quantity, zone
+			FROM deck_revision_cards
+			WHERE revision_id = $2
+		`
+		if _, err := tx.Exec(query, deck.ID, revision.ID); err != nil {
+			return err
+		}
+
+		restored, err = snapshotDeck(tx, deck.ID, message)
+		return err
+	})
+	return restored, err
+}
+
+// RevisionCards converts deck cards to the form they take in a revision, so the current state
+// of a deck can be compared with its revisions
+func RevisionCards(deckCards []*DeckCard) []*DeckRevisionCard {
+	cards := make([]*DeckRevisionCard, len(deckCards))
+	for i, deckCard := range deckCards {
+		cards[i] = &DeckRevisionCard{
+			CardID:   deckCard.CardID,
+			Quantity: deckCard.Quantity,
+			Zone:     deckCard.Zone,
+		}
+	}
+	return cards
+}
+
+// DiffDeckCards compares two card lists, matching cards by card and zone
+func DiffDeckCards(from, to []*DeckRevisionCard) (added, removed, changed []*DeckCardChange) {
+	type key struct {
+		cardID uuid.UUID
+		zone   string
+	}
+
+	changes := make(map[key]*DeckCardChange)
+	var order []key
+	change := func(card *DeckRevisionCard) *DeckCardChange {
+		k := key{cardID: card.CardID, zone: card.Zone}
+		c, ok := changes[k]
+		if !ok {
+			c = &DeckCardChange{CardID: card.CardID, Zone: card.Zone}
+			changes[k] = c
+			order = append(order, k)
+		}
+		return c
+	}
+	for _, card := range from {
+		change(card).FromQuantity += card.Quantity
+	}
+	for _, card := range to {
+		change(card).ToQuantity += card.Quantity
+	}
+
+	sort.Slice(order, func(i, j int) bool {
+		if order[i].zone != order[j].zone {
+			return order[i].zone < order[j].zone
+		}
+		return order[i].cardID.String() < order[j].cardID.String()
+	})
+
+	added, removed, changed = []*DeckCardChange{}, []*DeckCardChange{}, []*DeckCardChange{}
+	for _, k := range order {
+		c := changes[k]
+		switch {
+		case c.FromQuantity == 0:
+			added = append(added, c)
+		case c.ToQuantity == 0:
+			removed = append(removed, c)
+		case c.FromQuantity != c.ToQuantity:
+			changed = append(changed, c)
+		}
+	}
+
+	return added, removed, changed
+}
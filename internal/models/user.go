@@ -13,6 +13,7 @@ type User struct {
 	Username     string    `json:"username"`
 	Email        string    `json:"email"`
 	PasswordHash string    `json:"-"`
+	IsAdmin      bool      `json:"isAdmin"` // Admins may manage card imports
 	CreatedAt    time.Time `json:"createdAt"`
 	UpdatedAt    time.Time `json:"updatedAt"`
 }
@@ -60,7 +61,7 @@ func (s *UserStore) Create(user *User) error {
 func (s *UserStore) FindByID(id uuid.UUID) (*User, error) {
 	user := &User{}
 	query := `
-		SELECT id, username, email, password_hash, created_at, updated_at
+		SELECT id, username, email, password_hash, is_admin, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -70,6 +71,7 @@ func (s *UserStore) FindByID(id uuid.UUID) (*User, error) {
 		&user.Username,
 		&user.Email,
 		&user.PasswordHash,
+		&user.IsAdmin,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -84,7 +86,7 @@ func (s *UserStore) FindByID(id uuid.UUID) (*User, error) {
 func (s *UserStore) FindByEmail(email string) (*User, error) {
 	user := &User{}
 	query := `
-		SELECT id, username, email, password_hash, created_at, updated_at
+		SELECT id, username, email, password_hash, is_admin, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
@@ -94,6 +96,7 @@ func (s *UserStore) FindByEmail(email string) (*User, error) {
 		&user.Username,
 		&user.Email,
 		&user.PasswordHash,
+		&user.IsAdmin,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -108,7 +111,7 @@ func (s *UserStore) FindByEmail(email string) (*User, error) {
 func (s *UserStore) FindByUsername(username string) (*User, error) {
 	user := &User{}
 	query := `
-		SELECT id, username, email, password_hash, created_at, updated_at
+		SELECT id, username, email, password_hash, is_admin, created_at, updated_at
 		FROM users
 		WHERE username = $1
 	`
@@ -118,6 +121,7 @@ func (s *UserStore) FindByUsername(username string) (*User, error) {
 		&user.Username,
 		&user.Email,
 		&user.PasswordHash,
+		&user.IsAdmin,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
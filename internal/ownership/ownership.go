@@ -0,0 +1,289 @@
+package ownership
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/shiftregister-vg/card-craft/internal/models"
+)
+
+// Missing card export formats
+const (
+	FormatCSV      = "csv"
+	FormatWantList = "wantlist"
+)
+
+// ErrUnsupportedFormat is returned when missing cards are exported in an unknown format
+var ErrUnsupportedFormat = errors.New("unsupported missing cards format")
+
+// Result compares the cards of a deck with the copies a user owns
+type Result struct {
+	DeckID uuid.UUID `json:"deckId"`
+	// Cards holds one entry per card of the deck, in deck order
+	Cards   []*CardOwnership `json:"cards"`
+	Needed  int              `json:"needed"`
+	Owned   int              `json:"owned"`
+	Missing int              `json:"missing"`
+}
+
+// CardOwnership reports how many copies of a deck card the user owns and where they are
+type CardOwnership struct {
+	CardID  uuid.UUID `json:"cardId"`
+	Name    string    `json:"name"`
+	SetCode string    `json:"setCode"`
+	Number  string    `json:"number"`
+	Needed  int       `json:"needed"`
+	Owned   int       `json:"owned"`
+	Missing int       `json:"missing"`
+	// Reprintable reports whether other printings of the card can stand in for it
+	Reprintable bool           `json:"reprintable"`
+	Sources     []*OwnedCopies `json:"sources"`
+}
+
+// OwnedCopies are copies of a deck card held in one collection
+type OwnedCopies struct {
+	CollectionID   uuid.UUID `json:"collectionId"`
+	CollectionName string    `json:"collectionName"`
+	// CardID is the printing held, which differs from the deck card for reprints
+	CardID   uuid.UUID `json:"cardId"`
+	Quantity int       `json:"quantity"`
+	Reprint  bool      `json:"reprint"`
+}
+
+// Service compares decks with the collections of their users
+type Service struct {
+	db *sql.DB
+}
+
+// NewService creates a new ownership service
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+// deckEntry is a card the deck needs, with the details used to match reprints
+type deckEntry struct {
+	cardID    uuid.UUID
+	name      string
+	setCode   string
+	number    string
+	supertype string
+	quantity  int
+}
+
+// holding is a card held in a collection and the copies not yet assigned to a deck card
+type holding struct {
+	collectionID   uuid.UUID
+	collectionName string
+	cardID         uuid.UUID
+	name           string
+	supertype      string
+	remaining      int
+}
+
+// Compare reports which cards of a deck the user owns. Only the given collections are
+// searched, or every collection of the deck's game when collectionIDs is empty. Cards in the
+// maybeboard are not part of the deck and are left out.
+func (s *Service) Compare(ctx context.Context, deck *models.Deck, userID uuid.UUID, collectionIDs []uuid.UUID) (*Result, error) {
+	entries, err := s.loadDeck(ctx, deck)
+	if err != nil {
+		return nil, err
+	}
+
+	holdings, err := s.loadHoldings(ctx, strings.ToLower(deck.Game), userID, collectionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{DeckID: deck.ID, Cards: make([]*CardOwnership, len(entries))}
+	for i, entry := range entries {
+		result.Cards[i] = &CardOwnership{
+			CardID:      entry.cardID,
+			Name:        entry.name,
+			SetCode:     entry.setCode,
+			Number:      entry.number,
+			Needed:      entry.quantity,
+			Reprintable: reprintKey(deck.Game, entry.name, entry.supertype) != "",
+			Sources:     []*OwnedCopies{},
+		}
+	}
+
+	// Exact printings are assigned first, so a reprint only covers what they leave missing
+	for i, entry := range entries {
+		for _, h := range holdings {
+			if h.cardID == entry.cardID {
+				take(result.Cards[i], h, false)
+			}
+		}
+	}
+	for i, entry := range entries {
+		key := reprintKey(deck.Game, entry.name, entry.supertype)
+		if key == "" {
+			continue
+		}
+		for _, h := range holdings {
+			if h.cardID != entry.cardID && reprintKey(deck.Game, h.name, h.supertype) == key {
+				take(result.Cards[i], h, true)
+			}
+		}
+	}
+
+	for _, card := range result.Cards {
+		card.Missing = card.Needed - card.Owned
+		result.Needed += card.Needed
+		result.Owned += card.Owned
+		result.Missing += card.Missing
+	}
+
+	return result, nil
+}
+
+// take assigns the remaining copies of a holding to a deck card, up to what it still needs
+func take(card *CardOwnership, h *holding, reprint bool) {
+	n := min(card.Needed-card.Owned, h.remaining)
+	if n <= 0 {
+		return
+	}
+
+	h.remaining -= n
+	card.Owned += n
+	card.Sources = append(card.Sources, &OwnedCopies{
+		CollectionID:   h.collectionID,
+		CollectionName: h.collectionName,
+		CardID:         h.cardID,
+		Quantity:       n,
+		Reprint:        reprint,
+	})
+}
+
+// reprintKey returns the key under which printings of a card are interchangeable, or an empty
+// string when the game treats each printing as a different card. Pokémon only allows this for
+// Trainers and Energy, since Pokémon of the same name can have different attacks.
+func reprintKey(game, name, supertype string) string {
+	switch strings.ToLower(game) {
+	case "mtg", "lorcana", "starwars":
+		return strings.ToLower(name)
+	case "pokemon":
+		if strings.EqualFold(supertype, "Trainer") || strings.EqualFold(supertype, "Energy") {
+			return strings.ToLower(name)
+		}
+	}
+	return ""
+}
+
+// loadDeck fetches the cards of a deck, summing copies of a card across zones
+func (s *Service) loadDeck(ctx context.Context, deck *models.Deck) ([]*deckEntry, error) {
+	query := `
+		SELECT dc.card_id, c.name, c.set_code, c.number, COALESCE(p.supertype, ''), SUM(dc.quantity)
+		FROM deck_cards dc
+		JOIN cards c ON c.id = dc.card_id
+		LEFT JOIN pokemon_cards p ON p.card_id = c.id
+		WHERE dc.deck_id = $1 AND dc.zone <> $2
+		GROUP BY dc.card_id, c.name, c.set_code, c.number, p.supertype
+		ORDER BY MIN(dc.created_at), c.name
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, deck.ID, models.ZoneMaybeboard)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load deck cards: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*deckEntry
+	for rows.Next() {
+		entry := &deckEntry{}
+		err := rows.Scan(&entry.cardID, &entry.name, &entry.setCode, &entry.number, &entry.supertype, &entry.quantity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan deck card: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// loadHoldings fetches the cards in a user's collections for a game
+func (s *Service) loadHoldings(ctx context.Context, game string, userID uuid.UUID, collectionIDs []uuid.UUID) ([]*holding, error) {
+	query := `
+		SELECT col.id, col.name, cc.card_id, c.name, COALESCE(p.supertype, ''), cc.quantity
+		FROM collection_cards cc
+		JOIN collections col ON col.id = cc.collection_id
+		JOIN cards c ON c.id = cc.card_id
+		LEFT JOIN pokemon_cards p ON p.card_id = c.id
+		WHERE col.user_id = $1
+			AND c.game = $2
+			AND (cardinality($3::uuid[]) = 0 OR col.id = ANY($3))
+			AND cc.quantity > 0
+		ORDER BY col.created_at, col.id
+	`
+
+	ids := make([]string, len(collectionIDs))
+	for i, id := range collectionIDs {
+		ids[i] = id.String()
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, userID, game, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load collection cards: %w", err)
+	}
+	defer rows.Close()
+
+	var holdings []*holding
+	for rows.Next() {
+		h := &holding{}
+		err := rows.Scan(&h.collectionID, &h.collectionName, &h.cardID, &h.name, &h.supertype, &h.remaining)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan collection card: %w", err)
+		}
+		holdings = append(holdings, h)
+	}
+
+	return holdings, rows.Err()
+}
+
+// ExportMissing renders the cards a user still needs for a deck. The csv format has a header
+// row and one row per card; the want list has one "<count> <name>" line per card, naming the
+// printing only when reprints cannot stand in for it. An empty format selects csv.
+func ExportMissing(result *Result, game, format string) (string, error) {
+	var missing []*CardOwnership
+	for _, card := range result.Cards {
+		if card.Missing > 0 {
+			missing = append(missing, card)
+		}
+	}
+	sort.SliceStable(missing, func(i, j int) bool {
+		return strings.ToLower(missing[i].Name) < strings.ToLower(missing[j].Name)
+	})
+
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", FormatCSV:
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		_ = w.Write([]string{"quantity", "name", "set_code", "number", "game"})
+		for _, card := range missing {
+			_ = w.Write([]string{strconv.Itoa(card.Missing), card.Name, card.SetCode, card.Number, game})
+		}
+		w.Flush()
+		return buf.String(), w.Error()
+	case FormatWantList:
+		var sb strings.Builder
+		for _, card := range missing {
+			if card.Reprintable {
+				fmt.Fprintf(&sb, "%d %s\n", card.Missing, card.Name)
+			} else {
+				fmt.Fprintf(&sb, "%d %s %s %s\n", card.Missing, card.Name, strings.ToUpper(card.SetCode), card.Number)
+			}
+		}
+		return sb.String(), nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
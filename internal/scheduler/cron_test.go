@@ -0,0 +1,215 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{spec: "* * * * *"},
+		{spec: "0 3 * * *"},
+		{spec: "*/15 0-6/2 1,15 jan-mar mon-fri"},
+		{spec: "5/20 * * * 7"},
+		{spec: "@daily"},
+		{spec: " @Weekly "},
+		{spec: "0 3 * *", wantErr: true},
+		{spec: "0 3 * * * *", wantErr: true},
+		{spec: "60 * * * *", wantErr: true},
+		{spec: "* 24 * * *", wantErr: true},
+		{spec: "* * 0 * *", wantErr: true},
+		{spec: "* * * 13 *", wantErr: true},
+		{spec: "* * * * 8", wantErr: true},
+		{spec: "10-5 * * * *", wantErr: true},
+		{spec: "*/0 * * * *", wantErr: true},
+		{spec: "*/x * * * *", wantErr: true},
+		{spec: "* * * foo *", wantErr: true},
+		{spec: "@fortnightly", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := ParseCron(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseCron(%q) succeeded, want an error", tt.spec)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.spec, err)
+			}
+			if schedule.String() != tt.spec {
+				t.Errorf("String() = %q, want %q", schedule.String(), tt.spec)
+			}
+		})
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	utc := func(s string) time.Time {
+		parsed, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	local := func(s, zone string) time.Time {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04 MST", s+" "+zone, newYork)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want []time.Time
+	}{
+		{
+			name: "every minute",
+			spec: "* * * * *",
+			from: utc("2024-05-01 10:00").Add(30 * time.Second),
+			want: []time.Time{utc("2024-05-01 10:01"), utc("2024-05-01 10:02")},
+		},
+		{
+			name: "fixed time of day",
+			spec: "0 3 * * *",
+			from: utc("2024-05-01 03:00"),
+			want: []time.Time{utc("2024-05-02 03:00"), utc("2024-05-03 03:00")},
+		},
+		{
+			name: "range",
+			spec: "0 22-23 * * *",
+			from: utc("2024-05-01 21:30"),
+			want: []time.Time{utc("2024-05-01 22:00"), utc("2024-05-01 23:00"), utc("2024-05-02 22:00")},
+		},
+		{
+			name: "step",
+			spec: "*/20 * * * *",
+			from: utc("2024-05-01 10:05"),
+			want: []time.Time{utc("2024-05-01 10:20"), utc("2024-05-01 10:40"), utc("2024-05-01 11:00")},
+		},
+		{
+			name: "step over a range",
+			spec: "0 0-6/3 * * *",
+			from: utc("2024-05-01 01:00"),
+			want: []time.Time{utc("2024-05-01 03:00"), utc("2024-05-01 06:00"), utc("2024-05-02 00:00")},
+		},
+		{
+			name: "step from a value",
+			spec: "50/5 * * * *",
+			from: utc("2024-05-01 10:00"),
+			want: []time.Time{utc("2024-05-01 10:50"), utc("2024-05-01 10:55"), utc("2024-05-01 11:50")},
+		},
+		{
+			name: "list",
+			spec: "15,45 9 * * *",
+			from: utc("2024-05-01 09:20"),
+			want: []time.Time{utc("2024-05-01 09:45"), utc("2024-05-02 09:15")},
+		},
+		{
+			name: "month names skip to the next matching month",
+			spec: "0 0 1 jan,jul *",
+			from: utc("2024-02-10 00:00"),
+			want: []time.Time{utc("2024-07-01 00:00"), utc("2025-01-01 00:00")},
+		},
+		{
+			name: "day of month",
+			spec: "0 0 31 * *",
+			from: utc("2024-01-31 00:00"),
+			want: []time.Time{utc("2024-03-31 00:00"), utc("2024-05-31 00:00")},
+		},
+		{
+			name: "leap day",
+			spec: "0 0 29 2 *",
+			from: utc("2024-03-01 00:00"),
+			want: []time.Time{utc("2028-02-29 00:00")},
+		},
+		{
+			name: "day of week",
+			spec: "0 12 * * sat,sun",
+			from: utc("2024-05-01 00:00"), // a Wednesday
+			want: []time.Time{utc("2024-05-04 12:00"), utc("2024-05-05 12:00"), utc("2024-05-11 12:00")},
+		},
+		{
+			name: "seven is Sunday",
+			spec: "0 0 * * 7",
+			from: utc("2024-05-01 00:00"),
+			want: []time.Time{utc("2024-05-05 00:00")},
+		},
+		{
+			name: "day of month or day of week when both are restricted",
+			spec: "0 0 13 * fri",
+			from: utc("2024-09-01 00:00"),
+			want: []time.Time{utc("2024-09-06 00:00"), utc("2024-09-13 00:00"), utc("2024-09-20 00:00")},
+		},
+		{
+			name: "day of month and day of week when one is a step over *",
+			spec: "0 0 */2 * mon",
+			from: utc("2024-09-01 00:00"),
+			want: []time.Time{utc("2024-09-09 00:00"), utc("2024-09-23 00:00"), utc("2024-10-07 00:00")},
+		},
+		{
+			name: "time skipped when clocks go forward fires after the gap",
+			spec: "30 2 * * *",
+			from: local("2024-03-09 03:00", "EST"),
+			want: []time.Time{local("2024-03-10 03:30", "EDT"), local("2024-03-11 02:30", "EDT")},
+		},
+		{
+			name: "time repeated when clocks go back fires once",
+			spec: "30 1 * * *",
+			from: local("2024-11-03 00:00", "EDT"),
+			want: []time.Time{local("2024-11-03 01:30", "EDT"), local("2024-11-04 01:30", "EST")},
+		},
+		{
+			name: "hourly across clocks going back",
+			spec: "0 * * * *",
+			from: local("2024-11-03 00:30", "EDT"),
+			want: []time.Time{local("2024-11-03 01:00", "EDT"), local("2024-11-03 02:00", "EST")},
+		},
+		{
+			name: "hourly across clocks going forward",
+			spec: "0 * * * *",
+			from: local("2024-03-10 01:30", "EST"),
+			want: []time.Time{local("2024-03-10 03:00", "EDT"), local("2024-03-10 04:00", "EDT")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCron(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.spec, err)
+			}
+
+			from := tt.from
+			for i, want := range tt.want {
+				got := schedule.Next(from)
+				if !got.Equal(want) {
+					t.Fatalf("run %d: Next(%s) = %s, want %s", i+1, from, got, want)
+				}
+				from = got
+			}
+		})
+	}
+}
+
+func TestCronScheduleNextNever(t *testing.T) {
+	schedule, err := ParseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := schedule.Next(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Errorf("Next() = %s, want the zero time for February 30", got)
+	}
+}
@@ -2,64 +2,178 @@ package scheduler
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"math/rand/v2"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/shiftregister-vg/card-craft/internal/cards"
+	"github.com/shiftregister-vg/card-craft/internal/jobs"
 )
 
-// Scheduler handles periodic tasks for the application
+// DefaultSchedule is the cron expression used for games without a schedule of their own
+const DefaultSchedule = "0 3 * * *"
+
+// Options configures when the scheduler runs card imports
+type Options struct {
+	// DefaultSchedule is the cron expression for games missing from Schedules
+	DefaultSchedule string
+	// Schedules holds per-game cron expressions, keyed by game
+	Schedules map[string]string
+	// Jitter is the upper bound of a random delay added to each run, so replicas and
+	// games do not all hit the database and upstream APIs at the same moment
+	Jitter time.Duration
+	// MaxRuntime cancels an import that runs longer than this. Zero means no limit.
+	MaxRuntime time.Duration
+}
+
+// Scheduler runs each card importer on its own cron schedule
 type Scheduler struct {
-	importers []cards.CardImporter
-	store     *cards.CardStore
-	stop      chan struct{}
+	store   *cards.CardStore
+	jobs    *jobs.Service
+	options Options
+	entries []*entry
+
+	// ctx is the parent of every scheduled import; cancel aborts the ones still running
+	ctx     context.Context
+	cancel  context.CancelFunc
+	stop    chan struct{}
+	stopped sync.Once
+	wg      sync.WaitGroup
 }
 
-// NewScheduler creates a new scheduler instance
-func NewScheduler(store *cards.CardStore, importers ...cards.CardImporter) *Scheduler {
-	return &Scheduler{
-		importers: importers,
-		store:     store,
-		stop:      make(chan struct{}),
+// entry is an importer and the schedule it runs on
+type entry struct {
+	importer cards.CardImporter
+	schedule *CronSchedule
+}
+
+// NewScheduler creates a new scheduler instance. It returns an error if a schedule is not
+// a valid cron expression.
+func NewScheduler(store *cards.CardStore, jobService *jobs.Service, options Options, importers ...cards.CardImporter) (*Scheduler, error) {
+	if options.DefaultSchedule == "" {
+		options.DefaultSchedule = DefaultSchedule
+	}
+
+	entries := make([]*entry, 0, len(importers))
+	for _, importer := range importers {
+		spec, ok := options.Schedules[importer.GetGame()]
+		if !ok {
+			spec = options.DefaultSchedule
+		}
+
+		schedule, err := ParseCron(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule for %s: %w", importer.GetGame(), err)
+		}
+		entries = append(entries, &entry{importer: importer, schedule: schedule})
 	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		store:   store,
+		jobs:    jobService,
+		options: options,
+		entries: entries,
+		ctx:     ctx,
+		cancel:  cancel,
+		stop:    make(chan struct{}),
+	}, nil
 }
 
 // Start begins running scheduled tasks
 func (s *Scheduler) Start() {
-	go s.runCardImports()
+	for _, e := range s.entries {
+		log.Printf("Scheduling %s card imports: %s", e.importer.GetGame(), e.schedule)
+		s.wg.Add(1)
+		go s.run(e)
+	}
 }
 
-// Stop stops all scheduled tasks
-func (s *Scheduler) Stop() {
-	close(s.stop)
-}
+// Stop stops scheduling new imports and waits for running ones to finish, including those
+// started through the API. If ctx is done first, the running imports are cancelled, keeping
+// the batches they already committed, and Stop returns once they have wound down.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.stopped.Do(func() { close(s.stop) })
 
-// runCardImports runs the card import process daily
-func (s *Scheduler) runCardImports() {
-	ticker := time.NewTicker(24 * time.Hour)
-	defer ticker.Stop()
+	drained := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(drained)
+	}()
 
-	// Run immediately on start
-	s.importCards()
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		log.Printf("Cancelling card imports still running at shutdown")
+		s.cancel()
+		<-drained
+		err = ctx.Err()
+	}
+	s.cancel()
+
+	if shutdownErr := s.jobs.Shutdown(ctx); err == nil {
+		err = shutdownErr
+	}
+	return err
+}
+
+// run waits for each scheduled time of an importer and runs it, until the scheduler stops
+func (s *Scheduler) run(e *entry) {
+	defer s.wg.Done()
 
 	for {
+		next := e.schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Printf("Schedule %s for %s never fires again, stopping", e.schedule, e.importer.GetGame())
+			return
+		}
+		if s.options.Jitter > 0 {
+			next = next.Add(rand.N(s.options.Jitter))
+		}
+
+		timer := time.NewTimer(time.Until(next))
 		select {
-		case <-ticker.C:
-			s.importCards()
+		case <-timer.C:
+			s.importCards(e.importer)
 		case <-s.stop:
+			timer.Stop()
 			return
 		}
 	}
 }
 
-// importCards handles the card import process for all games
-func (s *Scheduler) importCards() {
-	for _, importer := range s.importers {
-		log.Printf("Starting scheduled card import for %s...", importer.GetGame())
-		if err := importer.Import(context.Background(), s.store); err != nil {
-			log.Printf("Error importing %s cards: %v", importer.GetGame(), err)
-			continue
-		}
-		log.Printf("Scheduled %s card import completed successfully", importer.GetGame())
+// importCards runs one scheduled import, unless another replica, the API or the import CLI is
+// already running it
+func (s *Scheduler) importCards(importer cards.CardImporter) {
+	game := importer.GetGame()
+	if err := cards.CheckConfigured(importer); err != nil {
+		log.Printf("Warning: skipping scheduled %s card import: %v", game, err)
+		return
+	}
+
+	ctx := s.ctx
+	if s.options.MaxRuntime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.options.MaxRuntime)
+		defer cancel()
+	}
+
+	log.Printf("Starting scheduled card import for %s...", game)
+	job, err := s.jobs.Run(ctx, game, jobs.TriggerScheduler, uuid.Nil, func(ctx context.Context) error {
+		return importer.Import(ctx, s.store)
+	})
+	if errors.Is(err, jobs.ErrImportRunning) {
+		log.Printf("Skipping scheduled %s card import: another instance is running it", game)
+		return
+	}
+	if err != nil {
+		log.Printf("Error importing %s cards: %v", game, err)
+		return
 	}
+	log.Printf("Scheduled %s card import completed successfully (job %s)", game, job.ID)
 }
@@ -0,0 +1,20 @@
+package search
+
+import (
+	"context"
+
+	"github.com/shiftregister-vg/card-craft/internal/cards"
+)
+
+// AdvancedSearch finds the cards matching a query written in the advanced search language,
+// such as `t:creature c>=rg cmc<=3 o:"draw a card" legal:modern` or `hp>=200 type:fire
+// stage:stage2`. Cards are ordered by name unless another sort is given. Queries that cannot
+// be parsed return a *ParseError, and cursors that cannot be read cards.ErrInvalidCursor.
+func (s *SearchService) AdvancedSearch(ctx context.Context, query string, opts cards.PageOptions) (*cards.CardPage, error) {
+	compiled, err := compileQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.cardStore.FindMatching(ctx, compiled.where, compiled.args, opts)
+}
@@ -0,0 +1,364 @@
+package search
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// compiled is an advanced search query turned into a SQL condition over cards c, mtg_cards m
+// and pokemon_cards p. Values are passed as parameters numbered from $1.
+//
+// A term is NULL for cards without the detail it looks at, such as cmc>3 for a Pokémon card.
+// SQL's three-valued logic keeps NULL through NOT, so negating a term never matches those
+// cards either: -cmc>3 finds MTG cards with a mana value of 3 or less.
+type compiled struct {
+	where string
+	args  []interface{}
+}
+
+// compileQuery parses an advanced search query and compiles it to SQL
+func compileQuery(query string) (*compiled, error) {
+	tree, err := parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &compiled{}
+	where, err := c.compile(tree)
+	if err != nil {
+		return nil, err
+	}
+	c.where = "COALESCE(" + where + ", false)"
+
+	return c, nil
+}
+
+// arg adds a query parameter and returns its placeholder
+func (c *compiled) arg(value interface{}) string {
+	c.args = append(c.args, value)
+	return fmt.Sprintf("$%d", len(c.args))
+}
+
+func (c *compiled) compile(n node) (string, error) {
+	switch n := n.(type) {
+	case *andNode:
+		return c.compileAll(n.children, " AND ")
+	case *orNode:
+		return c.compileAll(n.children, " OR ")
+	case *notNode:
+		child, err := c.compile(n.child)
+		if err != nil {
+			return "", err
+		}
+		return "NOT " + child, nil
+	case *termNode:
+		condition, err := c.compileTerm(n)
+		if err != nil {
+			return "", err
+		}
+		detail := termDetail(n.key)
+		if detail == "" {
+			return "COALESCE((" + condition + "), false)", nil
+		}
+		return "(CASE WHEN " + detail + " THEN COALESCE((" + condition + "), false) END)", nil
+	default:
+		return "", fmt.Errorf("unknown query node %T", n)
+	}
+}
+
+func (c *compiled) compileAll(children []node, join string) (string, error) {
+	parts := make([]string, len(children))
+	for i, child := range children {
+		part, err := c.compile(child)
+		if err != nil {
+			return "", err
+		}
+		parts[i] = part
+	}
+	return "(" + strings.Join(parts, join) + ")", nil
+}
+
+// compileTerm turns a single condition into SQL
+func (c *compiled) compileTerm(t *termNode) (string, error) {
+	switch t.key {
+	case "":
+		return "c.name ILIKE " + c.contains(t.value), nil
+
+	// Every game
+	case "name", "n":
+		if err := t.expectEquality(); err != nil {
+			return "", err
+		}
+		if t.op == "=" {
+			return "LOWER(c.name) = LOWER(" + c.arg(t.value) + ")", nil
+		}
+		return "c.name ILIKE " + c.contains(t.value), nil
+	case "game", "g":
+		if err := t.expectEquality(); err != nil {
+			return "", err
+		}
+		// Games are stored in lower case
+		return "c.game = " + c.arg(strings.ToLower(t.value)), nil
+	case "set", "s", "e", "edition":
+		if err := t.expectEquality(); err != nil {
+			return "", err
+		}
+		return "LOWER(c.set_code) = LOWER(" + c.arg(t.value) + ")", nil
+	case "rarity", "r":
+		if err := t.expectEquality(); err != nil {
+			return "", err
+		}
+		return "LOWER(c.rarity) = LOWER(" + c.arg(t.value) + ")", nil
+	case "number", "cn":
+		if err := t.expectEquality(); err != nil {
+			return "", err
+		}
+		return "LOWER(c.number) = LOWER(" + c.arg(t.value) + ")", nil
+	case "type", "t":
+		// MTG type lines, or the energy types of Pokémon
+		if err := t.expectEquality(); err != nil {
+			return "", err
+		}
+		value := c.arg(t.value)
+		return fmt.Sprintf("m.type_line ILIKE '%%' || %[1]s || '%%' OR %[2]s", value, arrayContains("p.types", value)), nil
+	case "oracle", "o", "text":
+		// MTG rules text, or the attack and ability text of Pokémon
+		if err := t.expectEquality(); err != nil {
+			return "", err
+		}
+		return "COALESCE(m.oracle_text, CASE WHEN p.id IS NOT NULL THEN c.search_text END) ILIKE " + c.contains(t.value), nil
+
+	// MTG
+	case "color", "colors", "c":
+		return c.compileColors(t, "m.colors", ">=")
+	case "identity", "id", "ci":
+		return c.compileColors(t, "m.color_identity", "<=")
+	case "cmc", "mv", "manavalue":
+		return c.compileNumber(t, "m.cmc")
+	case "power", "pow":
+		return c.compileNumber(t, numericText("m.power"))
+	case "toughness", "tou":
+		return c.compileNumber(t, numericText("m.toughness"))
+	case "loyalty", "loy":
+		return c.compileNumber(t, numericText("m.loyalty"))
+	case "legal", "format", "f":
+		return c.compileLegality(t, "legal")
+	case "banned":
+		return c.compileLegality(t, "banned")
+	case "restricted":
+		return c.compileLegality(t, "restricted")
+	case "keyword", "kw":
+		if err := t.expectEquality(); err != nil {
+			return "", err
+		}
+		return arrayContains("m.keywords", c.arg(t.value)), nil
+
+	// Pokémon
+	case "hp":
+		return c.compileNumber(t, "p.hp")
+	case "retreat":
+		return c.compileNumber(t, "cardinality(p.retreat_cost)")
+	case "stage":
+		if err := t.expectEquality(); err != nil {
+			return "", err
+		}
+		// stage:stage2, stage:2 and stage:"stage 2" all name the Stage 2 subtype
+		stage := strings.ToLower(strings.ReplaceAll(t.value, " ", ""))
+		if _, err := strconv.Atoi(stage); err == nil {
+			stage = "stage" + stage
+		}
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM unnest(p.subtypes) x WHERE LOWER(REPLACE(x, ' ', '')) = %s)", c.arg(stage)), nil
+	case "supertype", "st":
+		if err := t.expectEquality(); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("translate(LOWER(p.supertype), 'é', 'e') = translate(LOWER(%s), 'é', 'e')", c.arg(t.value)), nil
+	case "evolves", "from":
+		if err := t.expectEquality(); err != nil {
+			return "", err
+		}
+		return "LOWER(p.evolves_from) = LOWER(" + c.arg(t.value) + ")", nil
+	case "weakness", "weak":
+		if err := t.expectEquality(); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM jsonb_array_elements(CASE WHEN jsonb_typeof(p.weaknesses) = 'array' THEN p.weaknesses ELSE '[]' END) w
+			WHERE LOWER(w->>'type') = LOWER(%s))`, c.arg(t.value)), nil
+
+	default:
+		return "", t.errorf("unknown search key %q", t.key)
+	}
+}
+
+// termDetail returns the condition for a card to have the detail a search key looks at, or ""
+// for keys every card has
+func termDetail(key string) string {
+	switch key {
+	case "type", "t":
+		return "(m.id IS NOT NULL OR cardinality(p.types) > 0)"
+	case "oracle", "o", "text":
+		return "(m.id IS NOT NULL OR p.id IS NOT NULL)"
+
+	// MTG
+	case "color", "colors", "c", "identity", "id", "ci", "cmc", "mv", "manavalue",
+		"legal", "format", "f", "banned", "restricted", "keyword", "kw":
+		return "m.id IS NOT NULL"
+	case "power", "pow":
+		return numericText("m.power") + " IS NOT NULL"
+	case "toughness", "tou":
+		return numericText("m.toughness") + " IS NOT NULL"
+	case "loyalty", "loy":
+		return numericText("m.loyalty") + " IS NOT NULL"
+
+	// Pokémon
+	case "hp":
+		return "p.hp IS NOT NULL"
+	case "retreat":
+		return "p.retreat_cost IS NOT NULL"
+	case "stage", "supertype", "st", "evolves", "from", "weakness", "weak":
+		return "p.id IS NOT NULL"
+
+	default:
+		return ""
+	}
+}
+
+// contains returns a parameter matching any text containing value
+func (c *compiled) contains(value string) string {
+	return "'%' || " + c.arg(value) + " || '%'"
+}
+
+// compileNumber compares a numeric column
+func (c *compiled) compileNumber(t *termNode, column string) (string, error) {
+	n, err := strconv.ParseFloat(t.value, 64)
+	if err != nil {
+		return "", t.errorf("%s expects a number, got %q", t.key, t.value)
+	}
+	return fmt.Sprintf("%s %s %s::numeric", column, sqlOperator(t.op), c.arg(n)), nil
+}
+
+// compileLegality checks the status of an MTG card in a format
+func (c *compiled) compileLegality(t *termNode, status string) (string, error) {
+	if err := t.expectEquality(); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("m.legalities->>LOWER(%s) = %s", c.arg(t.value), c.arg(status)), nil
+}
+
+// compileColors compares an MTG color array with a set of colors. Like Scryfall, c:rg means
+// at least red and green while id:rg means within red and green, the colors a Commander deck
+// led by a red-green commander may play.
+func (c *compiled) compileColors(t *termNode, column, colonOp string) (string, error) {
+	value := strings.ToLower(t.value)
+	colors := fmt.Sprintf("COALESCE(%s, '{}')", column)
+
+	if value == "m" || value == "multicolor" {
+		if err := t.expectEquality(); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("m.id IS NOT NULL AND cardinality(%s) > 1", colors), nil
+	}
+
+	set, err := parseColors(value)
+	if err != nil {
+		return "", t.errorf("%s", err.Error())
+	}
+
+	op := t.op
+	if op == ":" {
+		op = colonOp
+	}
+
+	arr := c.arg(pq.Array(set)) + "::text[]"
+	size := len(set)
+	var condition string
+	switch op {
+	case ">=":
+		condition = fmt.Sprintf("%s @> %s", colors, arr)
+	case "<=":
+		condition = fmt.Sprintf("%s <@ %s", colors, arr)
+	case "=":
+		condition = fmt.Sprintf("%[1]s @> %[2]s AND %[1]s <@ %[2]s", colors, arr)
+	case "!=":
+		condition = fmt.Sprintf("NOT (%[1]s @> %[2]s AND %[1]s <@ %[2]s)", colors, arr)
+	case ">":
+		condition = fmt.Sprintf("%s @> %s AND cardinality(%s) > %d", colors, arr, colors, size)
+	case "<":
+		condition = fmt.Sprintf("%s <@ %s AND cardinality(%s) < %d", colors, arr, colors, size)
+	}
+
+	return "m.id IS NOT NULL AND " + condition, nil
+}
+
+// colorNames maps color words to their MTG color symbol
+var colorNames = map[string]string{
+	"white": "W",
+	"blue":  "U",
+	"black": "B",
+	"red":   "R",
+	"green": "G",
+}
+
+// parseColors reads a color value such as "rg", "red" or "c" for colorless
+func parseColors(value string) ([]string, error) {
+	if value == "c" || value == "colorless" {
+		return []string{}, nil
+	}
+	if symbol, ok := colorNames[value]; ok {
+		return []string{symbol}, nil
+	}
+
+	var set []string
+	seen := make(map[rune]bool)
+	for _, r := range value {
+		switch r {
+		case 'w', 'u', 'b', 'r', 'g':
+			if !seen[r] {
+				seen[r] = true
+				set = append(set, strings.ToUpper(string(r)))
+			}
+		default:
+			return nil, fmt.Errorf("unknown color %q, use letters from wubrg", string(r))
+		}
+	}
+	return set, nil
+}
+
+// arrayContains matches a text array holding value, ignoring case
+func arrayContains(column, value string) string {
+	return fmt.Sprintf("EXISTS (SELECT 1 FROM unnest(%s) x WHERE LOWER(x) = LOWER(%s))", column, value)
+}
+
+// numericText reads a text column as a number when it holds one, so */* creatures compare as NULL
+func numericText(column string) string {
+	return fmt.Sprintf("(CASE WHEN %[1]s ~ '^-?[0-9]+(\\.[0-9]+)?$' THEN %[1]s::numeric END)", column)
+}
+
+// sqlOperator maps a query operator to SQL
+func sqlOperator(op string) string {
+	switch op {
+	case ":":
+		return "="
+	case "!=":
+		return "<>"
+	default:
+		return op
+	}
+}
+
+// expectEquality rejects comparisons on keys that can only be matched
+func (t *termNode) expectEquality() error {
+	if t.op == ":" || t.op == "=" {
+		return nil
+	}
+	return t.errorf("%s does not support %s", t.key, t.op)
+}
+
+// errorf reports a problem with a term
+func (t *termNode) errorf(format string, args ...interface{}) *ParseError {
+	return &ParseError{Message: fmt.Sprintf(format, args...), Position: t.pos, Token: t.text}
+}
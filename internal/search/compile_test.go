@@ -0,0 +1,159 @@
+package search
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestCompileQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		where string
+		args  []interface{}
+	}{
+		{
+			query: "bolt",
+			where: "COALESCE(COALESCE((c.name ILIKE '%' || $1 || '%'), false), false)",
+			args:  []interface{}{"bolt"},
+		},
+		{
+			query: "name=Opt",
+			where: "COALESCE(COALESCE((LOWER(c.name) = LOWER($1)), false), false)",
+			args:  []interface{}{"Opt"},
+		},
+		{
+			query: "déjà vu",
+			where: "COALESCE((COALESCE((c.name ILIKE '%' || $1 || '%'), false) AND COALESCE((c.name ILIKE '%' || $2 || '%'), false)), false)",
+			args:  []interface{}{"déjà", "vu"},
+		},
+		{
+			query: "name:Déjà",
+			where: "COALESCE(COALESCE((c.name ILIKE '%' || $1 || '%'), false), false)",
+			args:  []interface{}{"Déjà"},
+		},
+		{
+			query: "g:MTG",
+			where: "COALESCE(COALESCE((c.game = $1), false), false)",
+			args:  []interface{}{"mtg"},
+		},
+		{
+			query: "cmc<=3",
+			where: "COALESCE((CASE WHEN m.id IS NOT NULL THEN COALESCE((m.cmc <= $1::numeric), false) END), false)",
+			args:  []interface{}{3.0},
+		},
+		{
+			// Negating a term keeps cards without its detail out
+			query: "-cmc>3",
+			where: "COALESCE(NOT (CASE WHEN m.id IS NOT NULL THEN COALESCE((m.cmc > $1::numeric), false) END), false)",
+			args:  []interface{}{3.0},
+		},
+		{
+			query: "-s:lea",
+			where: "COALESCE(NOT COALESCE((LOWER(c.set_code) = LOWER($1)), false), false)",
+			args:  []interface{}{"lea"},
+		},
+		{
+			query: "hp>=200 or r:rare",
+			where: "COALESCE(((CASE WHEN p.hp IS NOT NULL THEN COALESCE((p.hp >= $1::numeric), false) END) OR COALESCE((LOWER(c.rarity) = LOWER($2)), false)), false)",
+			args:  []interface{}{200.0, "rare"},
+		},
+		{
+			query: "legal:Modern",
+			where: "COALESCE((CASE WHEN m.id IS NOT NULL THEN COALESCE((m.legalities->>LOWER($1) = $2), false) END), false)",
+			args:  []interface{}{"Modern", "legal"},
+		},
+		{
+			query: "c:rg",
+			where: "COALESCE((CASE WHEN m.id IS NOT NULL THEN COALESCE((m.id IS NOT NULL AND COALESCE(m.colors, '{}') @> $1::text[]), false) END), false)",
+			args:  []interface{}{pq.Array([]string{"R", "G"})},
+		},
+		{
+			query: "id<=c",
+			where: "COALESCE((CASE WHEN m.id IS NOT NULL THEN COALESCE((m.id IS NOT NULL AND COALESCE(m.color_identity, '{}') <@ $1::text[]), false) END), false)",
+			args:  []interface{}{pq.Array([]string{})},
+		},
+		{
+			query: "stage:2",
+			where: "COALESCE((CASE WHEN p.id IS NOT NULL THEN COALESCE((EXISTS (SELECT 1 FROM unnest(p.subtypes) x WHERE LOWER(REPLACE(x, ' ', '')) = $1)), false) END), false)",
+			args:  []interface{}{"stage2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			compiled, err := compileQuery(tt.query)
+			if err != nil {
+				t.Fatalf("compileQuery(%q): %v", tt.query, err)
+			}
+			if compiled.where != tt.where {
+				t.Errorf("where = %s\nwant    %s", compiled.where, tt.where)
+			}
+			if !reflect.DeepEqual(compiled.args, tt.args) {
+				t.Errorf("args = %#v, want %#v", compiled.args, tt.args)
+			}
+		})
+	}
+}
+
+func TestCompileQueryDetails(t *testing.T) {
+	// Each key checks that the card has the detail it looks at
+	tests := []struct {
+		query  string
+		detail string
+	}{
+		{query: "t:creature", detail: "(m.id IS NOT NULL OR cardinality(p.types) > 0)"},
+		{query: `o:"draw a card"`, detail: "(m.id IS NOT NULL OR p.id IS NOT NULL)"},
+		{query: "kw:flying", detail: "m.id IS NOT NULL"},
+		{query: "pow>2", detail: numericText("m.power") + " IS NOT NULL"},
+		{query: "tou<2", detail: numericText("m.toughness") + " IS NOT NULL"},
+		{query: "loy=3", detail: numericText("m.loyalty") + " IS NOT NULL"},
+		{query: "retreat<=1", detail: "p.retreat_cost IS NOT NULL"},
+		{query: "weak:fire", detail: "p.id IS NOT NULL"},
+		{query: "evolves:pikachu", detail: "p.id IS NOT NULL"},
+		{query: "st:trainer", detail: "p.id IS NOT NULL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			compiled, err := compileQuery(tt.query)
+			if err != nil {
+				t.Fatalf("compileQuery(%q): %v", tt.query, err)
+			}
+			if !strings.HasPrefix(compiled.where, "COALESCE((CASE WHEN "+tt.detail+" THEN ") {
+				t.Errorf("where = %s, want it guarded by %s", compiled.where, tt.detail)
+			}
+		})
+	}
+}
+
+func TestCompileQueryErrors(t *testing.T) {
+	tests := []struct {
+		query   string
+		message string
+	}{
+		{query: "foo:bar", message: `unknown search key "foo"`},
+		{query: "cmc>x", message: `cmc expects a number, got "x"`},
+		{query: "t>creature", message: "t does not support >"},
+		{query: "set<lea", message: "set does not support <"},
+		{query: "c:xyz", message: `unknown color "x", use letters from wubrg`},
+		{query: "c>m", message: "c does not support >"},
+		{query: "a (b", message: "missing closing parenthesis"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := compileQuery(tt.query)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("compileQuery(%q) error = %v, want a *ParseError", tt.query, err)
+			}
+			if parseErr.Message != tt.message {
+				t.Errorf("compileQuery(%q) error = %q, want %q", tt.query, parseErr.Message, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParseError describes why an advanced search query could not be understood
+type ParseError struct {
+	Message string
+	// Position is the byte offset in the query where the problem was found
+	Position int
+	// Token is the part of the query at Position, empty at the end of the query
+	Token string
+}
+
+// Error implements the error interface
+func (e *ParseError) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("%s at position %d", e.Message, e.Position)
+	}
+	return fmt.Sprintf("%s at position %d (%q)", e.Message, e.Position, e.Token)
+}
+
+// The nodes of a parsed query
+type (
+	node interface{}
+
+	andNode struct {
+		children []node
+	}
+
+	orNode struct {
+		children []node
+	}
+
+	notNode struct {
+		child node
+	}
+
+	// termNode is a single condition, either key/op/value such as cmc<=3 or a bare word
+	// matched against the card name, in which case key and op are empty
+	termNode struct {
+		key   string
+		op    string
+		value string
+		pos   int
+		text  string
+	}
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenLParen
+	tokenRParen
+	tokenNot
+	tokenOr
+	tokenTerm
+)
+
+type token struct {
+	kind tokenKind
+	pos  int
+	text string
+	term *termNode
+}
+
+// comparison operators, longest first so <= is not read as <
+var operators = []string{"!=", "<=", ">=", ":", "=", "<", ">"}
+
+// lex splits a query into tokens
+func lex(query string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for {
+		for i < len(query) {
+			r, size := utf8.DecodeRuneInString(query[i:])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			i += size
+		}
+		if i >= len(query) {
+			tokens = append(tokens, token{kind: tokenEOF, pos: i})
+			return tokens, nil
+		}
+
+		start := i
+		switch query[i] {
+		case '(':
+			tokens = append(tokens, token{kind: tokenLParen, pos: i, text: "("})
+			i++
+			continue
+		case ')':
+			tokens = append(tokens, token{kind: tokenRParen, pos: i, text: ")"})
+			i++
+			continue
+		case '-':
+			tokens = append(tokens, token{kind: tokenNot, pos: i, text: "-"})
+			i++
+			continue
+		case '"':
+			value, end, err := readQuoted(query, i)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, token{kind: tokenTerm, pos: start, text: query[start:i],
+				term: &termNode{value: value, pos: start, text: query[start:i]}})
+			continue
+		}
+
+		// A key is a run of letters directly followed by an operator
+		j := i
+		for j < len(query) {
+			r, size := utf8.DecodeRuneInString(query[j:])
+			if !unicode.IsLetter(r) && r != '_' {
+				break
+			}
+			j += size
+		}
+		op := ""
+		if j > i {
+			for _, candidate := range operators {
+				if strings.HasPrefix(query[j:], candidate) {
+					op = candidate
+					break
+				}
+			}
+		}
+
+		if op == "" {
+			end := wordEnd(query, i)
+			word := query[i:end]
+			i = end
+			if strings.EqualFold(word, "or") {
+				tokens = append(tokens, token{kind: tokenOr, pos: start, text: word})
+				continue
+			}
+			if strings.EqualFold(word, "and") {
+				continue
+			}
+			tokens = append(tokens, token{kind: tokenTerm, pos: start, text: word,
+				term: &termNode{value: word, pos: start, text: word}})
+			continue
+		}
+
+		key := strings.ToLower(query[i:j])
+		i = j + len(op)
+		var value string
+		if i < len(query) && query[i] == '"' {
+			var err error
+			if value, i, err = readQuoted(query, i); err != nil {
+				return nil, err
+			}
+		} else {
+			end := wordEnd(query, i)
+			value = query[i:end]
+			i = end
+		}
+		if value == "" {
+			return nil, &ParseError{Message: fmt.Sprintf("missing value for %s", key), Position: start, Token: query[start:i]}
+		}
+
+		tokens = append(tokens, token{kind: tokenTerm, pos: start, text: query[start:i],
+			term: &termNode{key: key, op: op, value: value, pos: start, text: query[start:i]}})
+	}
+}
+
+// readQuoted reads a double-quoted string starting at i, returning its contents and the offset
+// just past the closing quote
+func readQuoted(query string, i int) (string, int, error) {
+	end := strings.IndexByte(query[i+1:], '"')
+	if end < 0 {
+		return "", 0, &ParseError{Message: "unterminated quote", Position: i, Token: query[i:]}
+	}
+	return query[i+1 : i+1+end], i + end + 2, nil
+}
+
+// wordEnd returns the offset where the unquoted word starting at i ends. The query is read
+// rune by rune, so a word never ends inside a multi-byte character.
+func wordEnd(query string, i int) int {
+	for i < len(query) {
+		r, size := utf8.DecodeRuneInString(query[i:])
+		if unicode.IsSpace(r) || r == '(' || r == ')' {
+			break
+		}
+		i += size
+	}
+	return i
+}
+
+// parser builds a query tree from tokens. Terms next to each other must all match, "or"
+// matches either side and binds looser, "-" negates and parentheses group.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parse turns an advanced search query into a tree of conditions
+func parse(query string) (node, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, &ParseError{Message: "empty query", Position: 0}
+	}
+
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &ParseError{Message: "unexpected " + describe(t), Position: t.pos, Token: t.text}
+	}
+
+	return n, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (node, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []node{first}
+	for p.peek().kind == tokenOr {
+		p.next()
+		child, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	if len(children) == 1 {
+		return first, nil
+	}
+	return &orNode{children: children}, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	var children []node
+	for {
+		switch p.peek().kind {
+		case tokenEOF, tokenRParen, tokenOr:
+			if len(children) == 0 {
+				t := p.peek()
+				return nil, &ParseError{Message: "expected a search term before " + describe(t), Position: t.pos, Token: t.text}
+			}
+			if len(children) == 1 {
+				return children[0], nil
+			}
+			return &andNode{children: children}, nil
+		}
+
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNot:
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{child: child}, nil
+	case tokenLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, &ParseError{Message: "missing closing parenthesis", Position: t.pos, Token: t.text}
+		}
+		return n, nil
+	case tokenTerm:
+		return t.term, nil
+	default:
+		return nil, &ParseError{Message: "unexpected " + describe(t), Position: t.pos, Token: t.text}
+	}
+}
+
+// describe names a token for error messages
+func describe(t token) string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of query"
+	case tokenLParen, tokenRParen:
+		return fmt.Sprintf("%q", t.text)
+	case tokenNot:
+		return "\"-\""
+	case tokenOr:
+		return "\"or\""
+	default:
+		return "term"
+	}
+}
@@ -0,0 +1,112 @@
+package search
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// formatNode renders a query tree in prefix form, such as (or (and a t:creature) (not cmc>3))
+func formatNode(n node) string {
+	switch n := n.(type) {
+	case *andNode:
+		return formatChildren("and", n.children)
+	case *orNode:
+		return formatChildren("or", n.children)
+	case *notNode:
+		return "(not " + formatNode(n.child) + ")"
+	case *termNode:
+		return n.key + n.op + n.value
+	default:
+		return "?"
+	}
+}
+
+func formatChildren(op string, children []node) string {
+	parts := []string{op}
+	for _, child := range children {
+		parts = append(parts, formatNode(child))
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "bolt", want: "bolt"},
+		{query: "lightning bolt", want: "(and lightning bolt)"},
+		{query: "t:creature cmc<=3", want: "(and t:creature cmc<=3)"},
+		{query: "T:Creature", want: "t:Creature"},
+		{query: "c>=rg pow!=2 tou>1 loy<4 hp=60", want: "(and c>=rg pow!=2 tou>1 loy<4 hp=60)"},
+		{query: `o:"draw a card"`, want: "o:draw a card"},
+		{query: `"serra angel"`, want: "serra angel"},
+		{query: "a and b", want: "(and a b)"},
+		{query: "a or b", want: "(or a b)"},
+		{query: "a OR b c", want: "(or a (and b c))"},
+		{query: "a b or c d", want: "(or (and a b) (and c d))"},
+		{query: "-t:land", want: "(not t:land)"},
+		{query: "--t:land", want: "(not (not t:land))"},
+		{query: "-(a or b) c", want: "(and (not (or a b)) c)"},
+		{query: "(a or b) (c or d)", want: "(and (or a b) (or c d))"},
+		{query: "((a))", want: "a"},
+		{query: "t:creature(c:r)", want: "(and t:creature c:r)"},
+		{query: "  spaced   out  ", want: "(and spaced out)"},
+		{query: "1/1", want: "1/1"},
+		// Unquoted words keep their multi-byte characters, including those with the bytes
+		// 0x85 and 0xA0 that would read as spaces on their own
+		{query: "déjà vu", want: "(and déjà vu)"},
+		{query: "name:Déjà", want: "name:Déjà"},
+		{query: "Ångström", want: "Ångström"},
+		{query: "supertype:Pokémon", want: "supertype:Pokémon"},
+		{query: "ÉCLAIR:x", want: "éclair:x"},
+		{query: "a\u00a0b", want: "(and a b)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			tree, err := parse(tt.query)
+			if err != nil {
+				t.Fatalf("parse(%q): %v", tt.query, err)
+			}
+			if got := formatNode(tree); got != tt.want || !utf8.ValidString(got) {
+				t.Errorf("parse(%q) = %s, want %s", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query    string
+		message  string
+		position int
+	}{
+		{query: "", message: "empty query", position: 0},
+		{query: "   ", message: "empty query", position: 0},
+		{query: "a)", message: `unexpected ")"`, position: 1},
+		{query: "(a", message: "missing closing parenthesis", position: 0},
+		{query: "()", message: `expected a search term before ")"`, position: 1},
+		{query: "or a", message: `expected a search term before "or"`, position: 0},
+		{query: "a or", message: "expected a search term before end of query", position: 4},
+		{query: "a -", message: "unexpected end of query", position: 3},
+		{query: `o:"draw`, message: "unterminated quote", position: 2},
+		{query: "cmc<", message: "missing value for cmc", position: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := parse(tt.query)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("parse(%q) error = %v, want a *ParseError", tt.query, err)
+			}
+			if parseErr.Message != tt.message || parseErr.Position != tt.position {
+				t.Errorf("parse(%q) error = %q at %d, want %q at %d",
+					tt.query, parseErr.Message, parseErr.Position, tt.message, tt.position)
+			}
+		})
+	}
+}
@@ -1,13 +1,22 @@
 package search
 
 import (
-	"strings"
+	"context"
 
 	"github.com/shiftregister-vg/card-craft/internal/cards"
+	"github.com/shiftregister-vg/card-craft/internal/models"
 	"github.com/shiftregister-vg/card-craft/internal/types"
 )
 
-// SearchService handles card search operations
+const (
+	// DefaultPageSize is the number of cards returned when no page size is given
+	DefaultPageSize = 50
+	// MaxPageSize bounds the number of cards returned in one page
+	MaxPageSize = 100
+)
+
+// SearchService handles card search operations. It is the single entry point for searching
+// cards; the queries themselves run through the card store.
 type SearchService struct {
 	cardStore *cards.CardStore
 }
@@ -19,99 +28,82 @@ func NewSearchService(cardStore *cards.CardStore) *SearchService {
 	}
 }
 
-// Search searches for cards based on the provided options
-func (s *SearchService) Search(opts types.SearchOptions) (*types.CardSearchResult, error) {
-	// If we're searching by set code and number, use FindByGameAndNumber
+// Search searches for cards based on the provided options. Results page by number, or by
+// cursor when First or After is set; an invalid sort returns cards.ErrInvalidSort and a
+// cursor that cannot be read cards.ErrInvalidCursor.
+func (s *SearchService) Search(ctx context.Context, opts types.SearchOptions) (*types.CardSearchResult, error) {
+	// A set code with a collector number finds that exact card; otherwise the name is searched
+	// within the set
 	if opts.SetCode != "" && opts.Name != "" {
-		card, err := s.cardStore.FindByGameAndNumber(opts.Game, opts.SetCode, opts.Name)
+		card, err := s.cardStore.FindByGameAndNumber(ctx, opts.Game, opts.SetCode, opts.Name)
 		if err != nil {
 			return nil, err
 		}
 		if card != nil {
-			return &types.CardSearchResult{
-				Cards:      []*types.Card{card},
-				TotalCount: 1,
-				Page:       1,
-				PageSize:   1,
-			}, nil
-		}
-		return &types.CardSearchResult{
-			Cards:      []*types.Card{},
-			TotalCount: 0,
-			Page:       1,
-			PageSize:   1,
-		}, nil
-	}
-
-	// Use the database's search capabilities
-	game := opts.Game
-	name := opts.Name
-	if name == "" {
-		// If no name is provided, get all cards for the game
-		cards, _, err := s.cardStore.FindByGame(game, 50, "")
-		if err != nil {
-			return nil, err
-		}
-		return &types.CardSearchResult{
-			Cards:      cards,
-			TotalCount: len(cards),
-			Page:       1,
-			PageSize:   50,
-		}, nil
-	}
-
-	// Search by name
-	cards, err := s.cardStore.SearchCards(name, game)
-	if err != nil {
-		return nil, err
-	}
-
-	// Apply additional filters if needed
-	var filteredCards []*types.Card
-	for _, card := range cards {
-		// Filter by set code
-		if opts.SetCode != "" && !strings.EqualFold(card.SetCode, opts.SetCode) {
-			continue
-		}
-
-		// Filter by rarity
-		if opts.Rarity != "" && !strings.EqualFold(card.Rarity, opts.Rarity) {
-			continue
+			opts.Number, opts.Name = opts.Name, ""
 		}
-
-		filteredCards = append(filteredCards, card)
 	}
 
-	// Calculate pagination
 	page := opts.Page
 	if page < 1 {
 		page = 1
 	}
 	pageSize := opts.PageSize
+	if opts.First > 0 {
+		pageSize = opts.First
+	}
 	if pageSize < 1 {
-		pageSize = 50
+		pageSize = DefaultPageSize
 	}
-
-	start := (page - 1) * pageSize
-	end := start + pageSize
-	if end > len(filteredCards) {
-		end = len(filteredCards)
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
 	}
+	opts.Page, opts.PageSize = page, pageSize
 
-	var pagedCards []*types.Card
-	if start < len(filteredCards) {
-		pagedCards = filteredCards[start:end]
+	// Filtering, ranking and pagination all happen in the database, so the total counts every
+	// match rather than a capped batch of candidates
+	found, err := s.cardStore.SearchCards(ctx, opts)
+	if err != nil {
+		return nil, err
 	}
 
+	if opts.After != "" {
+		// Cursor pages have no page number
+		page = 0
+	}
+	connection := CardConnection(found)
 	return &types.CardSearchResult{
-		Cards:      pagedCards,
-		TotalCount: len(filteredCards),
+		Cards:      found.Cards,
+		Edges:      connection.Edges,
+		PageInfo:   connection.PageInfo,
+		TotalCount: found.TotalCount,
 		Page:       page,
 		PageSize:   pageSize,
 	}, nil
 }
 
+// CardConnection turns a page of cards into a connection whose cursors continue under the
+// same sort
+func CardConnection(page *cards.CardPage) *models.CardConnection {
+	edges := make([]*models.CardEdge, len(page.Cards))
+	for i, card := range page.Cards {
+		edges[i] = &models.CardEdge{Node: card, Cursor: page.Cursors[i]}
+	}
+
+	pageInfo := &models.PageInfo{HasNextPage: page.HasNextPage}
+	if len(edges) > 0 {
+		pageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+
+	return &models.CardConnection{Edges: edges, PageInfo: pageInfo}
+}
+
 // FindByGameAndNumber finds a card by its game, set code, and number
-func (s *SearchService) FindByGameAndNumber(game, setCode, number string) (*types.Card, error) {
-	return s.cardStore.FindByGameAndNumber(game, setCode, number)
+func (s *SearchService) FindByGameAndNumber(ctx context.Context, game, setCode, number string) (*models.Card, error) {
+	return s.cardStore.FindByGameAndNumber(ctx, game, setCode, number)
+}
+
+// Filters returns the set codes and rarities that cards of a game can be filtered by
+func (s *SearchService) Filters(ctx context.Context, game string) (*types.CardFilters, error) {
+	return s.cardStore.Filters(ctx, game)
 }
@@ -148,7 +148,8 @@ func (s *Seeder) getTestDecks() []*models.Deck {
 			Name:        "My Pokemon Deck",
 			Description: "A test deck for Pokemon cards",
 			Game:        "pokemon",
-			IsPublic:    true,
+			Visibility:  models.VisibilityPublic,
+			Slug:        "my-pokemon-deck",
 		},
 		{
 			ID:          uuid.MustParse("00000000-0000-0000-0000-000000000202"),
@@ -156,7 +157,8 @@ func (s *Seeder) getTestDecks() []*models.Deck {
 			Name:        "Star Wars Deck",
 			Description: "A test deck for Star Wars cards",
 			Game:        "starwars",
-			IsPublic:    true,
+			Visibility:  models.VisibilityPublic,
+			Slug:        "star-wars-deck",
 		},
 		{
 			ID:          uuid.MustParse("00000000-0000-0000-0000-000000000203"),
@@ -164,7 +166,8 @@ func (s *Seeder) getTestDecks() []*models.Deck {
 			Name:        "Lorcana Deck",
 			Description: "A test deck for Disney Lorcana cards",
 			Game:        "lorcana",
-			IsPublic:    false,
+			Visibility:  models.VisibilityPrivate,
+			Slug:        "lorcana-deck",
 		},
 	}
 }
@@ -239,9 +242,9 @@ func (s *Seeder) Seed(ctx context.Context) error {
 		// Create test decks
 		for _, deck := range s.getTestDecks() {
 			_, err := tx.Exec(`
-				INSERT INTO decks (id, user_id, name, description, game, is_public, created_at, updated_at)
-				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-			`, deck.ID, deck.UserID, deck.Name, deck.Description, deck.Game, deck.IsPublic, time.Now(), time.Now())
+				INSERT INTO decks (id, user_id, name, description, game, visibility, slug, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			`, deck.ID, deck.UserID, deck.Name, deck.Description, deck.Game, deck.Visibility, deck.Slug, time.Now(), time.Now())
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,125 @@
+package simulator
+
+import (
+	"math/rand/v2"
+	"sort"
+)
+
+const (
+	// mtgMinHandSize is the hand size at which the simulated player stops taking mulligans
+	mtgMinHandSize = 5
+	// mtgPlayTurns is the number of turns checked for on-curve plays
+	mtgPlayTurns = 4
+	// mtgIdealLands is the number of lands the player keeps when bottoming cards
+	mtgIdealLands = 3
+)
+
+// simulateMTG deals opening hands with the London mulligan: each mulligan draws a fresh seven
+// and puts one more card on the bottom. A hand of size n is kept with between 2 and n-2 lands,
+// or once it is down to five cards. Games are played on the play, so the first draw is on turn 2.
+func simulateMTG(rng *rand.Rand, library []*Card, iterations int, t *tally) error {
+	lands := 0
+	t.lands = &lands
+	t.turnPlays = make([]int, mtgPlayTurns)
+
+	deck := make([]*Card, len(library))
+	for i := 0; i < iterations; i++ {
+		copy(deck, library)
+
+		var hand, rest []*Card
+		mulligans := 0
+		for {
+			shuffle(rng, deck)
+			hand, rest = bottomCards(deck[:HandSize], mulligans)
+			if keepMTGHand(hand) || len(hand) <= mtgMinHandSize {
+				break
+			}
+			mulligans++
+		}
+		t.keep(hand, mulligans)
+
+		for _, card := range hand {
+			if card.Land {
+				lands++
+			}
+		}
+
+		// The bottomed cards go under the library, so the draws come from the cards after the hand
+		draws := append(append([]*Card{}, deck[HandSize:]...), rest...)
+		for turn := 1; turn <= mtgPlayTurns; turn++ {
+			drawn := min(turn-1, len(draws))
+			if canPlayOnCurve(hand, draws[:drawn], turn) {
+				t.turnPlays[turn-1]++
+			}
+		}
+	}
+
+	return nil
+}
+
+// keepMTGHand reports whether a hand has a workable number of lands
+func keepMTGHand(hand []*Card) bool {
+	lands := 0
+	for _, card := range hand {
+		if card.Land {
+			lands++
+		}
+	}
+	return lands >= 2 && lands <= len(hand)-2
+}
+
+// bottomCards picks the cards to put on the bottom after n mulligans. Lands beyond the third go
+// first, then the spells with the highest mana value.
+func bottomCards(drawn []*Card, n int) (kept, bottomed []*Card) {
+	kept = append([]*Card{}, drawn...)
+	for ; n > 0 && len(kept) > 0; n-- {
+		lands := 0
+		for _, card := range kept {
+			if card.Land {
+				lands++
+			}
+		}
+		bottomLand := lands > mtgIdealLands || lands == len(kept)
+
+		pick := -1
+		for i, card := range kept {
+			if bottomLand && card.Land {
+				pick = i
+				break
+			}
+			if !bottomLand && !card.Land && (pick == -1 || card.ManaValue > kept[pick].ManaValue) {
+				pick = i
+			}
+		}
+		if pick == -1 {
+			pick = len(kept) - 1
+		}
+
+		bottomed = append(bottomed, kept[pick])
+		kept = append(kept[:pick], kept[pick+1:]...)
+	}
+	return kept, bottomed
+}
+
+// canPlayOnCurve reports whether, having played a land each turn, the player can cast a spell
+// with mana value equal to the turn number
+func canPlayOnCurve(hand, draws []*Card, turn int) bool {
+	lands := 0
+	var spells []int
+	for _, group := range [][]*Card{hand, draws} {
+		for _, card := range group {
+			if card.Land {
+				lands++
+			} else {
+				spells = append(spells, card.ManaValue)
+			}
+		}
+	}
+	if lands < turn {
+		return false
+	}
+
+	sort.Ints(spells)
+	i := sort.SearchInts(spells, turn)
+	return i < len(spells) && spells[i] == turn
+}
@@ -0,0 +1,64 @@
+package simulator
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// pokemonPrizeCards is the number of prize cards set aside after the opening hand
+const pokemonPrizeCards = 6
+
+// simulatePokemon deals opening hands with Pokémon's mulligan rule: a hand without a Basic
+// Pokémon is shuffled back and a new hand of seven drawn. Six prize cards are then set aside
+// from the top of the deck.
+func simulatePokemon(rng *rand.Rand, library []*Card, iterations int, t *tally) error {
+	if len(library) < HandSize+pokemonPrizeCards {
+		return fmt.Errorf("%w: %d cards", ErrDeckTooSmall, len(library))
+	}
+
+	hasBasic := false
+	for _, card := range library {
+		if card.BasicPokemon {
+			hasBasic = true
+			break
+		}
+	}
+	if !hasBasic {
+		return ErrNoBasicPokemon
+	}
+
+	basicFirstHand := 0
+	t.basicFirstHand = &basicFirstHand
+
+	deck := make([]*Card, len(library))
+	for i := 0; i < iterations; i++ {
+		copy(deck, library)
+
+		mulligans := 0
+		for {
+			shuffle(rng, deck)
+			if handHasBasic(deck[:HandSize]) {
+				break
+			}
+			mulligans++
+		}
+		if mulligans == 0 {
+			basicFirstHand++
+		}
+
+		t.keep(deck[:HandSize], mulligans)
+		t.count(deck[HandSize:HandSize+pokemonPrizeCards], t.prized)
+	}
+
+	return nil
+}
+
+// handHasBasic reports whether a hand holds a Basic Pokémon to start the game with
+func handHasBasic(hand []*Card) bool {
+	for _, card := range hand {
+		if card.BasicPokemon {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,315 @@
+package simulator
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/shiftregister-vg/card-craft/internal/models"
+)
+
+const (
+	// HandSize is the size of an opening hand in the supported games
+	HandSize = 7
+	// DefaultIterations is the number of games simulated when no count is given
+	DefaultIterations = 1000
+	// MaxIterations bounds the work of a single simulation
+	MaxIterations = 100000
+)
+
+var (
+	// ErrDeckTooSmall is returned when a deck has too few cards to draw an opening hand
+	ErrDeckTooSmall = errors.New("deck has too few cards to simulate")
+	// ErrNoBasicPokemon is returned for Pokémon decks that could never keep an opening hand
+	ErrNoBasicPokemon = errors.New("deck has no Basic Pokémon, so every opening hand would be a mulligan")
+)
+
+// Card is one copy of a card in the simulated library
+type Card struct {
+	ID   uuid.UUID
+	Name string
+
+	// MTG details
+	Land      bool
+	ManaValue int
+
+	// Pokémon details
+	BasicPokemon bool
+}
+
+// Result aggregates the outcome of the simulated games
+type Result struct {
+	DeckID     uuid.UUID `json:"deckId"`
+	Iterations int       `json:"iterations"`
+	// Seed reproduces the same games when passed back in
+	Seed     int `json:"seed"`
+	HandSize int `json:"handSize"`
+
+	MulliganRate        float64 `json:"mulliganRate"`
+	AverageMulligans    float64 `json:"averageMulligans"`
+	AverageKeptHandSize float64 `json:"averageKeptHandSize"`
+
+	// MTG results
+	AverageLandsInHand *float64    `json:"averageLandsInHand"`
+	TurnPlayRates      []*TurnRate `json:"turnPlayRates"`
+
+	// Pokémon results
+	BasicInOpeningHandRate *float64 `json:"basicInOpeningHandRate"`
+
+	Cards []*CardResult `json:"cards"`
+}
+
+// TurnRate is how often a play was possible on a given turn
+type TurnRate struct {
+	Turn int     `json:"turn"`
+	Rate float64 `json:"rate"`
+}
+
+// CardResult reports how often a card, counting every printing of its name, showed up
+type CardResult struct {
+	CardID          uuid.UUID `json:"cardId"`
+	Name            string    `json:"name"`
+	Copies          int       `json:"copies"`
+	OpeningHandRate float64   `json:"openingHandRate"`
+	// PrizedRate is how often at least one copy was among the prize cards, for Pokémon
+	PrizedRate float64 `json:"prizedRate"`
+}
+
+// Service simulates games with saved decks
+type Service struct {
+	db *sql.DB
+}
+
+// NewService creates a new simulator service
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+// SimulateDeck loads the main deck of a saved deck and simulates opening hands with it
+func (s *Service) SimulateDeck(ctx context.Context, deck *models.Deck, iterations int, seed int) (*Result, error) {
+	library, err := s.loadLibrary(ctx, deck)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := Simulate(deck.Game, library, iterations, seed)
+	if err != nil {
+		return nil, err
+	}
+	result.DeckID = deck.ID
+
+	return result, nil
+}
+
+// Simulate plays the given number of games with a library of cards, shuffling with a random
+// generator seeded from seed so the same seed always gives the same result
+func Simulate(game string, library []*Card, iterations int, seed int) (*Result, error) {
+	if len(library) < HandSize {
+		return nil, fmt.Errorf("%w: %d cards", ErrDeckTooSmall, len(library))
+	}
+
+	rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed)))
+	tally := newTally(library)
+
+	var err error
+	switch strings.ToLower(game) {
+	case "mtg":
+		err = simulateMTG(rng, library, iterations, tally)
+	case "pokemon":
+		err = simulatePokemon(rng, library, iterations, tally)
+	default:
+		simulateDraws(rng, library, iterations, tally)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := tally.result(iterations)
+	result.Seed = seed
+	return result, nil
+}
+
+// RandomSeed picks a seed for a simulation that was not given one. It fits in a GraphQL Int,
+// so it can be passed back to repeat the run.
+func RandomSeed() int {
+	return int(rand.Int32())
+}
+
+// simulateDraws deals opening hands without mulligans, for games without mulligan rules here
+func simulateDraws(rng *rand.Rand, library []*Card, iterations int, t *tally) {
+	deck := make([]*Card, len(library))
+	for i := 0; i < iterations; i++ {
+		copy(deck, library)
+		shuffle(rng, deck)
+		t.keep(deck[:HandSize], 0)
+	}
+}
+
+// shuffle randomizes the order of a deck in place
+func shuffle(rng *rand.Rand, deck []*Card) {
+	rng.Shuffle(len(deck), func(i, j int) {
+		deck[i], deck[j] = deck[j], deck[i]
+	})
+}
+
+// tally accumulates the outcome of each simulated game
+type tally struct {
+	mulligans     int
+	mulliganGames int
+	keptCards     int
+
+	// inHand and prized count, per card name, the games where a copy was in the kept hand or
+	// the prizes
+	names      []string
+	cards      map[string]*CardResult
+	inHand     map[string]int
+	prized     map[string]int
+	seenInGame map[string]bool
+
+	// Game-specific counters, left nil when they do not apply
+	lands          *int
+	turnPlays      []int
+	basicFirstHand *int
+}
+
+func newTally(library []*Card) *tally {
+	t := &tally{
+		cards:      make(map[string]*CardResult),
+		inHand:     make(map[string]int),
+		prized:     make(map[string]int),
+		seenInGame: make(map[string]bool),
+	}
+	for _, card := range library {
+		key := strings.ToLower(card.Name)
+		result, ok := t.cards[key]
+		if !ok {
+			result = &CardResult{CardID: card.ID, Name: card.Name}
+			t.cards[key] = result
+			t.names = append(t.names, key)
+		}
+		result.Copies++
+	}
+	return t
+}
+
+// keep records the hand a game kept and how many mulligans it took
+func (t *tally) keep(hand []*Card, mulligans int) {
+	t.mulligans += mulligans
+	if mulligans > 0 {
+		t.mulliganGames++
+	}
+	t.keptCards += len(hand)
+	t.count(hand, t.inHand)
+}
+
+// count adds one to each card name present in a group of cards
+func (t *tally) count(cards []*Card, counts map[string]int) {
+	clear(t.seenInGame)
+	for _, card := range cards {
+		key := strings.ToLower(card.Name)
+		if !t.seenInGame[key] {
+			t.seenInGame[key] = true
+			counts[key]++
+		}
+	}
+}
+
+// result turns the tally into rates over the simulated games
+func (t *tally) result(iterations int) *Result {
+	n := float64(iterations)
+	result := &Result{
+		Iterations:          iterations,
+		HandSize:            HandSize,
+		MulliganRate:        float64(t.mulliganGames) / n,
+		AverageMulligans:    float64(t.mulligans) / n,
+		AverageKeptHandSize: float64(t.keptCards) / n,
+		TurnPlayRates:       []*TurnRate{},
+		Cards:               make([]*CardResult, len(t.names)),
+	}
+
+	if t.lands != nil {
+		average := float64(*t.lands) / n
+		result.AverageLandsInHand = &average
+	}
+	for i, plays := range t.turnPlays {
+		result.TurnPlayRates = append(result.TurnPlayRates, &TurnRate{Turn: i + 1, Rate: float64(plays) / n})
+	}
+	if t.basicFirstHand != nil {
+		rate := float64(*t.basicFirstHand) / n
+		result.BasicInOpeningHandRate = &rate
+	}
+
+	for i, key := range t.names {
+		card := t.cards[key]
+		card.OpeningHandRate = float64(t.inHand[key]) / n
+		card.PrizedRate = float64(t.prized[key]) / n
+		result.Cards[i] = card
+	}
+
+	return result
+}
+
+// loadLibrary fetches the main deck of a deck as one Card per copy, in a fixed order so a
+// seed always shuffles the same library the same way
+func (s *Service) loadLibrary(ctx context.Context, deck *models.Deck) ([]*Card, error) {
+	query := `
+		SELECT dc.card_id, c.name, dc.quantity,
+			COALESCE(m.type_line, ''), COALESCE(m.cmc, 0),
+			COALESCE(p.supertype, ''), p.subtypes
+		FROM deck_cards dc
+		JOIN cards c ON c.id = dc.card_id
+		LEFT JOIN mtg_cards m ON m.card_id = c.id
+		LEFT JOIN pokemon_cards p ON p.card_id = c.id
+		WHERE dc.deck_id = $1 AND dc.zone = $2
+		ORDER BY dc.created_at, c.name, dc.card_id
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, deck.ID, models.ZoneMain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load deck cards: %w", err)
+	}
+	defer rows.Close()
+
+	var library []*Card
+	for rows.Next() {
+		card := &Card{}
+		var quantity int
+		var typeLine, supertype string
+		var cmc float64
+		var subtypes []string
+		err := rows.Scan(&card.ID, &card.Name, &quantity, &typeLine, &cmc, &supertype, pq.Array(&subtypes))
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan deck card: %w", err)
+		}
+
+		front, _, _ := strings.Cut(typeLine, " // ")
+		card.Land = strings.Contains(strings.ToLower(front), "land")
+		card.ManaValue = int(cmc)
+		card.BasicPokemon = isBasicPokemon(supertype, subtypes)
+
+		for i := 0; i < quantity; i++ {
+			c := *card
+			library = append(library, &c)
+		}
+	}
+
+	return library, rows.Err()
+}
+
+// isBasicPokemon reports whether a card can be put into play as a Basic Pokémon
+func isBasicPokemon(supertype string, subtypes []string) bool {
+	if !strings.EqualFold(supertype, "Pokémon") && !strings.EqualFold(supertype, "Pokemon") {
+		return false
+	}
+	for _, subtype := range subtypes {
+		if strings.EqualFold(subtype, "Basic") {
+			return true
+		}
+	}
+	return false
+}
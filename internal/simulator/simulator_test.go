@@ -0,0 +1,107 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// testLibrary builds a library of copies of cards, giving each name its own ID
+func testLibrary(cards ...Card) []*Card {
+	var library []*Card
+	for _, card := range cards {
+		card.ID = uuid.NewSHA1(uuid.NameSpaceOID, []byte(card.Name))
+		for i := 0; i < 4; i++ {
+			c := card
+			library = append(library, &c)
+		}
+	}
+	return library
+}
+
+func TestSimulateIsDeterministic(t *testing.T) {
+	mtg := testLibrary(
+		Card{Name: "Island", Land: true},
+		Card{Name: "Mountain", Land: true},
+		Card{Name: "Plains", Land: true},
+		Card{Name: "Forest", Land: true},
+		Card{Name: "Opt", ManaValue: 1},
+		Card{Name: "Counterspell", ManaValue: 2},
+		Card{Name: "Lightning Bolt", ManaValue: 1},
+		Card{Name: "Shock", ManaValue: 1},
+		Card{Name: "Fireball", ManaValue: 5},
+		Card{Name: "Serra Angel", ManaValue: 5},
+	)
+	pokemon := testLibrary(
+		Card{Name: "Pikachu", BasicPokemon: true},
+		Card{Name: "Raichu"},
+		Card{Name: "Potion"},
+		Card{Name: "Professor's Research"},
+		Card{Name: "Lightning Energy"},
+		Card{Name: "Switch"},
+	)
+	other := testLibrary(
+		Card{Name: "Alpha"},
+		Card{Name: "Beta"},
+		Card{Name: "Gamma"},
+	)
+
+	tests := []struct {
+		game    string
+		library []*Card
+	}{
+		{game: "mtg", library: mtg},
+		{game: "pokemon", library: pokemon},
+		{game: "lorcana", library: other},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.game, func(t *testing.T) {
+			first, err := Simulate(tt.game, tt.library, 500, 42)
+			if err != nil {
+				t.Fatalf("Simulate: %v", err)
+			}
+			second, err := Simulate(tt.game, tt.library, 500, 42)
+			if err != nil {
+				t.Fatalf("Simulate: %v", err)
+			}
+			if !reflect.DeepEqual(first, second) {
+				t.Errorf("two runs with seed 42 differ:\n%s\n%s", describe(first), describe(second))
+			}
+			if first.Seed != 42 || first.Iterations != 500 {
+				t.Errorf("result has seed %d and %d iterations, want 42 and 500", first.Seed, first.Iterations)
+			}
+
+			other, err := Simulate(tt.game, tt.library, 500, 43)
+			if err != nil {
+				t.Fatalf("Simulate: %v", err)
+			}
+			if reflect.DeepEqual(first.Cards, other.Cards) {
+				t.Errorf("seeds 42 and 43 gave the same card rates")
+			}
+		})
+	}
+}
+
+func TestSimulateRejectsUnplayableDecks(t *testing.T) {
+	if _, err := Simulate("mtg", testLibrary(Card{Name: "Island", Land: true}), 10, 1); !errors.Is(err, ErrDeckTooSmall) {
+		t.Errorf("Simulate with 4 cards returned %v, want ErrDeckTooSmall", err)
+	}
+
+	noBasics := testLibrary(Card{Name: "Raichu"}, Card{Name: "Potion"}, Card{Name: "Switch"}, Card{Name: "Lightning Energy"})
+	if _, err := Simulate("pokemon", noBasics, 10, 1); !errors.Is(err, ErrNoBasicPokemon) {
+		t.Errorf("Simulate without Basic Pokémon returned %v, want ErrNoBasicPokemon", err)
+	}
+}
+
+// describe formats a result for a failure message
+func describe(result *Result) string {
+	s := fmt.Sprintf("mulligans %.3f, kept %.3f", result.MulliganRate, result.AverageKeptHandSize)
+	for _, card := range result.Cards {
+		s += fmt.Sprintf(", %s %.3f", card.Name, card.OpeningHandRate)
+	}
+	return s
+}
@@ -0,0 +1,112 @@
+package stats
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// mtgCurveTop is the mana value from which cards share the last bucket of the mana curve
+const mtgCurveTop = 7
+
+// mtgColors lists the mana symbols counted as pips, in WUBRG order followed by colorless
+var mtgColors = []string{"W", "U", "B", "R", "G", "C"}
+
+// mtgCardTypes lists the card types of the type breakdown. Tribal is reported as Kindred, its
+// current name.
+var mtgCardTypes = []string{"Creature", "Planeswalker", "Battle", "Instant", "Sorcery", "Artifact", "Enchantment", "Kindred", "Land"}
+
+// manaSymbol matches one symbol of a mana cost, such as {2}, {W} or {G/P}
+var manaSymbol = regexp.MustCompile(`\{([^}]+)\}`)
+
+// mtgStats fills in the mana curve, color pips and type breakdown of an MTG deck
+func mtgStats(stats *DeckStats, entries []*entry) {
+	curve := newCounter()
+	for i := 0; i < mtgCurveTop; i++ {
+		curve.add(strconv.Itoa(i), 0)
+	}
+	curve.add(strconv.Itoa(mtgCurveTop)+"+", 0)
+
+	pips := newCounter(mtgColors...)
+	types := newCounter(mtgCardTypes...)
+
+	var manaValueTotal float64
+	var spells int
+	for _, e := range entries {
+		cardTypes := frontFaceTypes(e.typeLine)
+		for _, t := range cardTypes {
+			types.add(t, e.quantity)
+		}
+
+		for color, n := range countPips(e.manaCost) {
+			pips.add(color, n*e.quantity)
+		}
+
+		if e.cmc == nil || hasType(cardTypes, "Land") {
+			continue
+		}
+		manaValue := int(math.Floor(*e.cmc))
+		if manaValue >= mtgCurveTop {
+			curve.add(strconv.Itoa(mtgCurveTop)+"+", e.quantity)
+		} else {
+			curve.add(strconv.Itoa(manaValue), e.quantity)
+		}
+		manaValueTotal += *e.cmc * float64(e.quantity)
+		spells += e.quantity
+	}
+
+	stats.ManaCurve = curve.order
+	stats.ColorPips = pips.nonZero()
+	stats.TypeBreakdown = types.nonZero()
+	if spells > 0 {
+		average := math.Round(manaValueTotal/float64(spells)*100) / 100
+		stats.AverageManaValue = &average
+	}
+}
+
+// countPips counts the colored and colorless mana symbols of a mana cost. Hybrid symbols count
+// towards each of their colors, and Phyrexian symbols towards their color.
+func countPips(manaCost string) map[string]int {
+	pips := make(map[string]int)
+	for _, match := range manaSymbol.FindAllStringSubmatch(manaCost, -1) {
+		for _, part := range strings.Split(strings.ToUpper(match[1]), "/") {
+			for _, color := range mtgColors {
+				if part == color {
+					pips[color]++
+				}
+			}
+		}
+	}
+	return pips
+}
+
+// frontFaceTypes returns the card types on the front face of a type line, such as
+// "Legendary Creature — Elf Druid // Land"
+func frontFaceTypes(typeLine string) []string {
+	front, _, _ := strings.Cut(typeLine, " // ")
+	front, _, _ = strings.Cut(front, "—")
+
+	var types []string
+	for _, word := range strings.Fields(front) {
+		if strings.EqualFold(word, "Tribal") {
+			word = "Kindred"
+		}
+		for _, t := range mtgCardTypes {
+			if strings.EqualFold(word, t) {
+				types = append(types, t)
+			}
+		}
+	}
+	return types
+}
+
+// hasType reports whether a list of card types contains one
+func hasType(types []string, cardType string) bool {
+	for _, t := range types {
+		if t == cardType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package stats
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// maxEvolutionStages bounds the walk down an evolution line, as a guard against cyclic data
+const maxEvolutionStages = 4
+
+// pokemonStats fills in the supertype, type and energy breakdowns of a Pokémon deck
+func pokemonStats(stats *DeckStats, entries []*entry) {
+	supertypes := newCounter("Pokémon", "Trainer", "Energy")
+	pokemonTypes := newCounter()
+	energyTypes := newCounter()
+
+	for _, e := range entries {
+		switch {
+		case isPokemon(e):
+			supertypes.add("Pokémon", e.quantity)
+			for _, t := range e.types {
+				pokemonTypes.add(t, e.quantity)
+			}
+		case strings.EqualFold(e.supertype, "Trainer"):
+			supertypes.add("Trainer", e.quantity)
+		case strings.EqualFold(e.supertype, "Energy"):
+			supertypes.add("Energy", e.quantity)
+			energyTypes.add(energyType(e), e.quantity)
+		}
+	}
+
+	stats.Supertypes = supertypes.nonZero()
+	stats.PokemonTypes = pokemonTypes.byCount()
+	stats.EnergyTypes = energyTypes.byCount()
+}
+
+// isPokemon reports whether a card is a Pokémon, as opposed to a Trainer or Energy
+func isPokemon(e *entry) bool {
+	return strings.EqualFold(e.supertype, "Pokémon") || strings.EqualFold(e.supertype, "Pokemon")
+}
+
+// energyType returns the type of energy a card provides. Basic Energy are named after their
+// type; Special Energy without a type of their own are grouped together.
+func energyType(e *entry) string {
+	if len(e.types) > 0 {
+		return e.types[0]
+	}
+
+	if hasSubtypeFold(e.subtypes, "Special") {
+		return "Special"
+	}
+
+	name := strings.TrimPrefix(e.name, "Basic ")
+	if t, ok := strings.CutSuffix(name, " Energy"); ok && t != "" {
+		return t
+	}
+	return "Special"
+}
+
+// hasSubtypeFold reports whether the subtypes contain a value, ignoring case
+func hasSubtypeFold(subtypes []string, subtype string) bool {
+	for _, s := range subtypes {
+		if strings.EqualFold(s, subtype) {
+			return true
+		}
+	}
+	return false
+}
+
+// evolutionLines follows each evolved Pokémon of the deck down to its Basic and reports the
+// stages the deck is missing. A line starts at a Pokémon none of whose evolutions are in the deck.
+func (s *Service) evolutionLines(ctx context.Context, entries []*entry) ([]*EvolutionLine, error) {
+	type pokemon struct {
+		name        string
+		count       int
+		evolvesFrom string
+		evolvesTo   []string
+	}
+
+	byName := make(map[string]*pokemon)
+	var order []*pokemon
+	for _, e := range entries {
+		if !isPokemon(e) {
+			continue
+		}
+		key := strings.ToLower(e.name)
+		p, ok := byName[key]
+		if !ok {
+			p = &pokemon{name: e.name, evolvesFrom: e.evolvesFrom, evolvesTo: e.evolvesTo}
+			byName[key] = p
+			order = append(order, p)
+		}
+		p.count += e.quantity
+	}
+
+	lines := []*EvolutionLine{}
+	for _, top := range order {
+		if top.evolvesFrom == "" {
+			continue
+		}
+		evolvedFurther := false
+		for _, name := range top.evolvesTo {
+			if byName[strings.ToLower(name)] != nil {
+				evolvedFurther = true
+				break
+			}
+		}
+		if evolvedFurther {
+			continue
+		}
+
+		line := &EvolutionLine{
+			Stages:  []*EvolutionStage{{Name: top.name, Count: top.count}},
+			Missing: []string{},
+		}
+		next := top.evolvesFrom
+		for depth := 1; next != "" && depth < maxEvolutionStages; depth++ {
+			if p := byName[strings.ToLower(next)]; p != nil {
+				line.Stages = append(line.Stages, &EvolutionStage{Name: p.name, Count: p.count})
+				next = p.evolvesFrom
+				continue
+			}
+
+			line.Stages = append(line.Stages, &EvolutionStage{Name: next})
+			line.Missing = append(line.Missing, next)
+
+			// The deck does not say what a missing stage evolves from, so ask the card data
+			var err error
+			if next, err = s.evolvesFrom(ctx, next); err != nil {
+				return nil, err
+			}
+		}
+		line.Complete = len(line.Missing) == 0
+
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// evolvesFrom looks up the Pokémon a card of the given name evolves from
+func (s *Service) evolvesFrom(ctx context.Context, name string) (string, error) {
+	query := `
+		SELECT COALESCE(p.evolves_from, '')
+		FROM cards c
+		JOIN pokemon_cards p ON p.card_id = c.id
+		WHERE c.game = 'pokemon' AND LOWER(c.name) = LOWER($1)
+		ORDER BY p.evolves_from IS NULL
+		LIMIT 1
+	`
+
+	var evolvesFrom string
+	err := s.db.QueryRowContext(ctx, query, name).Scan(&evolvesFrom)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up %s: %w", name, err)
+	}
+
+	return evolvesFrom, nil
+}
@@ -0,0 +1,307 @@
+package stats
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/shiftregister-vg/card-craft/internal/models"
+)
+
+// DefaultHandSize is the size of an opening hand in the supported games
+const DefaultHandSize = 7
+
+// DeckStats summarizes the cards of a deck. The game-specific lists are empty for other games.
+type DeckStats struct {
+	// CardCount is the number of cards in the deck itself, leaving out the sideboard and maybeboard
+	CardCount int `json:"cardCount"`
+
+	// MTG stats
+	ManaCurve        []*StatCount `json:"manaCurve"`
+	AverageManaValue *float64     `json:"averageManaValue"`
+	ColorPips        []*StatCount `json:"colorPips"`
+	TypeBreakdown    []*StatCount `json:"typeBreakdown"`
+
+	// Pokémon stats
+	Supertypes     []*StatCount     `json:"supertypes"`
+	PokemonTypes   []*StatCount     `json:"pokemonTypes"`
+	EnergyTypes    []*StatCount     `json:"energyTypes"`
+	EvolutionLines []*EvolutionLine `json:"evolutionLines"`
+
+	DrawProbabilities []*DrawProbability `json:"drawProbabilities"`
+}
+
+// StatCount is the number of cards in one bucket of a breakdown
+type StatCount struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// EvolutionLine is a chain of Pokémon from the highest stage in the deck down to its Basic
+type EvolutionLine struct {
+	// Stages lists the stages from the highest down, including those missing from the deck
+	Stages []*EvolutionStage `json:"stages"`
+	// Missing names the stages the deck has no copies of
+	Missing  []string `json:"missing"`
+	Complete bool     `json:"complete"`
+}
+
+// EvolutionStage is one Pokémon of an evolution line and how many copies the deck has
+type EvolutionStage struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// DrawProbability is the chance of drawing at least one copy of a card in an opening hand
+type DrawProbability struct {
+	CardID      uuid.UUID `json:"cardId"`
+	Name        string    `json:"name"`
+	Copies      int       `json:"copies"`
+	HandSize    int       `json:"handSize"`
+	Probability float64   `json:"probability"`
+}
+
+// entry is a deck card together with the game data the stats need
+type entry struct {
+	cardID   uuid.UUID
+	name     string
+	quantity int
+	zone     string
+
+	// MTG details
+	manaCost string
+	cmc      *float64
+	typeLine string
+
+	// Pokémon details
+	supertype   string
+	types       []string
+	subtypes    []string
+	evolvesFrom string
+	evolvesTo   []string
+}
+
+// Service computes deck statistics
+type Service struct {
+	db *sql.DB
+}
+
+// NewService creates a new stats service
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+// Compute returns the statistics of a deck, with opening hand probabilities for the given
+// cards. A hand size of zero selects DefaultHandSize.
+func (s *Service) Compute(ctx context.Context, deck *models.Deck, drawCardIDs []uuid.UUID, handSize int) (*DeckStats, error) {
+	if handSize <= 0 {
+		handSize = DefaultHandSize
+	}
+
+	entries, err := s.loadEntries(ctx, deck)
+	if err != nil {
+		return nil, err
+	}
+
+	// The commander starts in the command zone, so it counts towards the deck but is never drawn
+	inDeck := inZones(entries, models.ZoneMain, models.ZoneCommander)
+
+	stats := &DeckStats{
+		CardCount:         countCards(inDeck),
+		ManaCurve:         []*StatCount{},
+		ColorPips:         []*StatCount{},
+		TypeBreakdown:     []*StatCount{},
+		Supertypes:        []*StatCount{},
+		PokemonTypes:      []*StatCount{},
+		EnergyTypes:       []*StatCount{},
+		EvolutionLines:    []*EvolutionLine{},
+		DrawProbabilities: drawProbabilities(inZones(entries, models.ZoneMain), drawCardIDs, handSize),
+	}
+
+	switch strings.ToLower(deck.Game) {
+	case "mtg":
+		mtgStats(stats, inDeck)
+	case "pokemon":
+		pokemonStats(stats, inDeck)
+		if stats.EvolutionLines, err = s.evolutionLines(ctx, inDeck); err != nil {
+			return nil, err
+		}
+	}
+
+	return stats, nil
+}
+
+// loadEntries fetches the deck cards joined with their base and game-specific data
+func (s *Service) loadEntries(ctx context.Context, deck *models.Deck) ([]*entry, error) {
+	query := `
+		SELECT dc.card_id, c.name, dc.quantity, dc.zone,
+			COALESCE(m.mana_cost, ''), m.cmc, COALESCE(m.type_line, ''),
+			COALESCE(p.supertype, ''), p.types, p.subtypes, COALESCE(p.evolves_from, ''), p.evolves_to
+		FROM deck_cards dc
+		JOIN cards c ON c.id = dc.card_id
+		LEFT JOIN mtg_cards m ON m.card_id = c.id
+		LEFT JOIN pokemon_cards p ON p.card_id = c.id
+		WHERE dc.deck_id = $1
+		ORDER BY c.name, dc.zone
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, deck.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load deck cards: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*entry
+	for rows.Next() {
+		e := &entry{}
+		var cmc sql.NullFloat64
+		err := rows.Scan(
+			&e.cardID,
+			&e.name,
+			&e.quantity,
+			&e.zone,
+			&e.manaCost,
+			&cmc,
+			&e.typeLine,
+			&e.supertype,
+			pq.Array(&e.types),
+			pq.Array(&e.subtypes),
+			&e.evolvesFrom,
+			pq.Array(&e.evolvesTo),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan deck card: %w", err)
+		}
+		if cmc.Valid {
+			e.cmc = &cmc.Float64
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// drawProbabilities computes the opening hand odds of the chosen cards. Every printing of a
+// card's name in the library counts as a copy.
+func drawProbabilities(library []*entry, cardIDs []uuid.UUID, handSize int) []*DrawProbability {
+	copiesByName := make(map[string]int)
+	for _, e := range library {
+		copiesByName[strings.ToLower(e.name)] += e.quantity
+	}
+	librarySize := countCards(library)
+
+	probabilities := []*DrawProbability{}
+	for _, cardID := range cardIDs {
+		for _, e := range library {
+			if e.cardID != cardID {
+				continue
+			}
+
+			copies := copiesByName[strings.ToLower(e.name)]
+			probabilities = append(probabilities, &DrawProbability{
+				CardID:      cardID,
+				Name:        e.name,
+				Copies:      copies,
+				HandSize:    handSize,
+				Probability: AtLeastOne(librarySize, copies, handSize),
+			})
+			break
+		}
+	}
+
+	return probabilities
+}
+
+// AtLeastOne returns the hypergeometric probability of drawing at least one of the given copies
+// in a hand drawn from a library
+func AtLeastOne(librarySize, copies, handSize int) float64 {
+	if copies <= 0 || librarySize <= 0 {
+		return 0
+	}
+	if handSize > librarySize {
+		handSize = librarySize
+	}
+
+	// The chance of missing every copy is the product of drawing a non-copy on each draw
+	miss := 1.0
+	for i := 0; i < handSize; i++ {
+		others := librarySize - copies - i
+		if others <= 0 {
+			return 1
+		}
+		miss *= float64(others) / float64(librarySize-i)
+	}
+
+	return 1 - miss
+}
+
+// inZones returns the entries placed in any of the given zones
+func inZones(entries []*entry, zones ...string) []*entry {
+	var matched []*entry
+	for _, e := range entries {
+		for _, zone := range zones {
+			if e.zone == zone {
+				matched = append(matched, e)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// countCards returns the total number of cards across all entries
+func countCards(entries []*entry) int {
+	total := 0
+	for _, e := range entries {
+		total += e.quantity
+	}
+	return total
+}
+
+// counter accumulates a breakdown, remembering the order labels were first seen in
+type counter struct {
+	counts map[string]*StatCount
+	order  []*StatCount
+}
+
+func newCounter(labels ...string) *counter {
+	c := &counter{counts: make(map[string]*StatCount)}
+	for _, label := range labels {
+		c.add(label, 0)
+	}
+	return c
+}
+
+func (c *counter) add(label string, n int) {
+	count, ok := c.counts[label]
+	if !ok {
+		count = &StatCount{Label: label}
+		c.counts[label] = count
+		c.order = append(c.order, count)
+	}
+	count.Count += n
+}
+
+// nonZero returns the buckets with at least one card
+func (c *counter) nonZero() []*StatCount {
+	counts := []*StatCount{}
+	for _, count := range c.order {
+		if count.Count > 0 {
+			counts = append(counts, count)
+		}
+	}
+	return counts
+}
+
+// byCount returns the buckets with at least one card, largest first
+func (c *counter) byCount() []*StatCount {
+	counts := c.nonZero()
+	sort.SliceStable(counts, func(i, j int) bool {
+		return counts[i].Count > counts[j].Count
+	})
+	return counts
+}
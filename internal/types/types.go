@@ -1,25 +1,9 @@
 package types
 
 import (
-	"time"
-
-	"github.com/google/uuid"
+	"github.com/shiftregister-vg/card-craft/internal/models"
 )
 
-// Card represents a trading card in the database
-type Card struct {
-	ID        uuid.UUID
-	Name      string
-	Game      string
-	SetCode   string
-	SetName   string
-	Number    string
-	Rarity    string
-	ImageURL  string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-}
-
 // CardInput represents the input for creating or updating a card
 type CardInput struct {
 	Name     string  `json:"name"`
@@ -36,12 +20,20 @@ type DeckInput struct {
 	Name        string  `json:"name"`
 	Description *string `json:"description"`
 	Game        string  `json:"game"`
+	Visibility  *string `json:"visibility"`
+}
+
+// PublicDeckFilters represents the filters for browsing public decks
+type PublicDeckFilters struct {
+	Query  *string `json:"query"`
+	CardID *string `json:"cardId"`
 }
 
 // DeckCardInput represents the input for adding a card to a deck
 type DeckCardInput struct {
-	CardID   string `json:"cardId"`
-	Quantity int    `json:"quantity"`
+	CardID   string  `json:"cardId"`
+	Quantity int     `json:"quantity"`
+	Zone     *string `json:"zone"`
 }
 
 // SearchOptions represents the options for searching cards
@@ -50,10 +42,15 @@ type SearchOptions struct {
 	SetCode   string `json:"setCode"`
 	Rarity    string `json:"rarity"`
 	Name      string `json:"name"`
+	Number    string `json:"number"` // Exact collector number
 	Page      int    `json:"page"`
 	PageSize  int    `json:"pageSize"`
 	SortBy    string `json:"sortBy"`
 	SortOrder string `json:"sortOrder"`
+
+	// First and After page by cursor instead of by page number
+	First int    `json:"first"`
+	After string `json:"after"`
 }
 
 // CardFilters represents the available filters for a game
@@ -62,10 +59,13 @@ type CardFilters struct {
 	Rarities []string `json:"rarities"`
 }
 
-// CardSearchResult represents the result of a card search
+// CardSearchResult represents the result of a card search. Edges and PageInfo hold the same
+// cards with the cursors to continue from, as in a CardConnection.
 type CardSearchResult struct {
-	Cards      []*Card `json:"cards"`
-	TotalCount int     `json:"totalCount"`
-	Page       int     `json:"page"`
-	PageSize   int     `json:"pageSize"`
+	Cards      []*models.Card     `json:"cards"`
+	Edges      []*models.CardEdge `json:"edges"`
+	PageInfo   *models.PageInfo   `json:"pageInfo"`
+	TotalCount int                `json:"totalCount"`
+	Page       int                `json:"page"`
+	PageSize   int                `json:"pageSize"`
 }
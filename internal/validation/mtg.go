@@ -0,0 +1,250 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shiftregister-vg/card-craft/internal/models"
+)
+
+// mtgMaxSideboard is the largest sideboard allowed outside Commander formats. A companion
+// counts towards it.
+const mtgMaxSideboard = 15
+
+// mtgFormat describes the deck construction rules of a Magic: The Gathering format
+type mtgFormat struct {
+	minCards  int
+	maxCards  int // zero means no upper limit
+	maxCopies int
+	commander bool
+}
+
+// mtgFormats maps Scryfall legality keys to their deck construction rules
+var mtgFormats = map[string]mtgFormat{
+	"standard":        {minCards: 60, maxCopies: 4},
+	"pioneer":         {minCards: 60, maxCopies: 4},
+	"modern":          {minCards: 60, maxCopies: 4},
+	"legacy":          {minCards: 60, maxCopies: 4},
+	"vintage":         {minCards: 60, maxCopies: 4},
+	"pauper":          {minCards: 60, maxCopies: 4},
+	"historic":        {minCards: 60, maxCopies: 4},
+	"timeless":        {minCards: 60, maxCopies: 4},
+	"explorer":        {minCards: 60, maxCopies: 4},
+	"alchemy":         {minCards: 60, maxCopies: 4},
+	"premodern":       {minCards: 60, maxCopies: 4},
+	"oldschool":       {minCards: 60, maxCopies: 4},
+	"penny":           {minCards: 60, maxCopies: 4},
+	"commander":       {minCards: 100, maxCards: 100, maxCopies: 1, commander: true},
+	"duel":            {minCards: 100, maxCards: 100, maxCopies: 1, commander: true},
+	"paupercommander": {minCards: 100, maxCards: 100, maxCopies: 1, commander: true},
+	"brawl":           {minCards: 100, maxCards: 100, maxCopies: 1, commander: true},
+	"standardbrawl":   {minCards: 60, maxCards: 60, maxCopies: 1, commander: true},
+	"predh":           {minCards: 100, maxCards: 100, maxCopies: 1, commander: true},
+	"oathbreaker":     {minCards: 60, maxCards: 60, maxCopies: 1},
+}
+
+// validateMTG checks an MTG deck against the legalities and construction rules of a format
+func validateMTG(entries []*DeckEntry, format string) ([]*Violation, error) {
+	rules, ok := mtgFormats[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not an MTG format", ErrUnsupportedFormat, format)
+	}
+
+	var violations []*Violation
+
+	// The commander is part of the deck; the sideboard and companion are not
+	total := countCards(inZones(entries, models.ZoneMain, models.ZoneCommander))
+	switch {
+	case rules.maxCards > 0 && rules.minCards == rules.maxCards && total != rules.minCards:
+		violations = append(violations, &Violation{
+			Code:    CodeDeckSize,
+			Message: fmt.Sprintf("deck must contain exactly %d cards, found %d", rules.minCards, total),
+		})
+	case total < rules.minCards:
+		violations = append(violations, &Violation{
+			Code:    CodeDeckSize,
+			Message: fmt.Sprintf("deck must contain at least %d cards, found %d", rules.minCards, total),
+		})
+	case rules.maxCards > 0 && total > rules.maxCards:
+		violations = append(violations, &Violation{
+			Code:    CodeDeckSize,
+			Message: fmt.Sprintf("deck may contain at most %d cards, found %d", rules.maxCards, total),
+		})
+	}
+
+	if !rules.commander {
+		if sideboard := countCards(inZones(entries, models.ZoneSideboard, models.ZoneCompanion)); sideboard > mtgMaxSideboard {
+			violations = append(violations, &Violation{
+				Code:    CodeSideboardSize,
+				Message: fmt.Sprintf("sideboard may contain at most %d cards, found %d", mtgMaxSideboard, sideboard),
+			})
+		}
+	}
+
+	for _, group := range copiesByName(entries) {
+		entry := group.entry
+		if !entry.HasDetails {
+			violations = append(violations, cardViolation(CodeMissingCardData, entry,
+				fmt.Sprintf("%s has no MTG card data and cannot be checked", entry.Name)))
+			continue
+		}
+
+		maxCopies := rules.maxCopies
+		switch entry.Legalities[format] {
+		case "legal":
+		case "restricted":
+			if group.quantity > 1 {
+				violations = append(violations, cardViolation(CodeRestricted, entry,
+					fmt.Sprintf("%s is restricted in %s and may appear only once, found %d", entry.Name, format, group.quantity)))
+			}
+			continue
+		case "banned":
+			violations = append(violations, cardViolation(CodeBanned, entry,
+				fmt.Sprintf("%s is banned in %s", entry.Name, format)))
+			continue
+		default:
+			violations = append(violations, cardViolation(CodeNotLegal, entry,
+				fmt.Sprintf("%s is not legal in %s", entry.Name, format)))
+			continue
+		}
+
+		if limit, unlimited := copyExemption(entry); unlimited {
+			continue
+		} else if limit > 0 {
+			maxCopies = limit
+		}
+
+		if group.quantity > maxCopies {
+			violations = append(violations, cardViolation(CodeCopyLimit, entry,
+				fmt.Sprintf("deck may contain at most %d copies of %s in %s, found %d", maxCopies, entry.Name, format, group.quantity)))
+		}
+	}
+
+	if rules.commander {
+		violations = append(violations, validateCommander(entries)...)
+	}
+
+	return violations, nil
+}
+
+// validateCommander checks the deck's commanders and that every card fits within their color
+// identity. Cards in the commander zone are the commanders; decks without any fall back to
+// treating the legendary creatures in the list as candidates.
+func validateCommander(entries []*DeckEntry) []*Violation {
+	if chosen := inZones(entries, models.ZoneCommander); len(chosen) > 0 {
+		return validateChosenCommanders(entries, chosen)
+	}
+
+	var commanders []*DeckEntry
+	for _, entry := range entries {
+		if canBeCommander(entry) && entry.Quantity == 1 {
+			commanders = append(commanders, entry)
+		}
+	}
+
+	if len(commanders) == 0 {
+		return []*Violation{{
+			Code:    CodeCommander,
+			Message: "deck must include a legendary creature to serve as its commander",
+		}}
+	}
+
+	// With several candidates, use the one whose color identity covers the most of the deck
+	var best []*Violation
+	for i, commander := range commanders {
+		violations := identityViolations(entries, commander.ColorIdentity, commander.Name)
+		if i == 0 || len(violations) < len(best) {
+			best = violations
+		}
+	}
+
+	return best
+}
+
+// validateChosenCommanders checks the cards placed in the commander zone. A deck may have two
+// commanders, as with partners, and its color identity is the union of theirs.
+func validateChosenCommanders(entries, commanders []*DeckEntry) []*Violation {
+	var violations []*Violation
+
+	if total := countCards(commanders); total > 2 {
+		violations = append(violations, &Violation{
+			Code:    CodeCommander,
+			Message: fmt.Sprintf("deck may have at most 2 commanders, found %d", total),
+		})
+	}
+
+	var identity []string
+	var names []string
+	for _, commander := range commanders {
+		if commander.HasDetails && !canBeCommander(commander) {
+			violations = append(violations, cardViolation(CodeCommander, commander,
+				fmt.Sprintf("%s cannot be a commander", commander.Name)))
+		}
+		identity = append(identity, commander.ColorIdentity...)
+		names = append(names, commander.Name)
+	}
+
+	return append(violations, identityViolations(entries, identity, strings.Join(names, " and "))...)
+}
+
+// identityViolations reports the cards with colors outside a commander's color identity
+func identityViolations(entries []*DeckEntry, colors []string, commanderName string) []*Violation {
+	identity := make(map[string]bool)
+	for _, color := range colors {
+		identity[strings.ToUpper(color)] = true
+	}
+
+	var violations []*Violation
+	for _, entry := range entries {
+		if outside := outsideIdentity(entry.ColorIdentity, identity); len(outside) > 0 {
+			violations = append(violations, cardViolation(CodeColorIdentity, entry,
+				fmt.Sprintf("%s has colors %s outside the color identity of %s",
+					entry.Name, strings.Join(outside, ""), commanderName)))
+		}
+	}
+	return violations
+}
+
+// canBeCommander reports whether a card may lead a Commander deck
+func canBeCommander(entry *DeckEntry) bool {
+	typeLine := strings.ToLower(entry.TypeLine)
+	if strings.Contains(typeLine, "legendary") && strings.Contains(typeLine, "creature") {
+		return true
+	}
+	return strings.Contains(strings.ToLower(entry.OracleText), "can be your commander")
+}
+
+// outsideIdentity returns the sorted colors that are not part of the commander's identity
+func outsideIdentity(colors []string, identity map[string]bool) []string {
+	var outside []string
+	for _, color := range colors {
+		if !identity[strings.ToUpper(color)] {
+			outside = append(outside, strings.ToUpper(color))
+		}
+	}
+	sort.Strings(outside)
+	return outside
+}
+
+// copyExemption reports cards that ignore the usual copy limit: basic lands and cards whose
+// rules text allows any number (or a specific number) of copies
+func copyExemption(entry *DeckEntry) (limit int, unlimited bool) {
+	if strings.Contains(strings.ToLower(entry.TypeLine), "basic land") {
+		return 0, true
+	}
+
+	text := strings.ToLower(entry.OracleText)
+	if strings.Contains(text, "a deck can have any number of cards named") {
+		return 0, true
+	}
+
+	words := map[string]int{"seven": 7, "nine": 9}
+	for word, n := range words {
+		if strings.Contains(text, "a deck can have up to "+word+" cards named") {
+			return n, false
+		}
+	}
+
+	return 0, false
+}
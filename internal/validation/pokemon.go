@@ -0,0 +1,97 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	defaultPokemonFormat = "standard"
+	pokemonDeckSize      = 60
+	pokemonMaxCopies     = 4
+)
+
+// pokemonFormats lists the Pokémon formats that share the standard deck construction rules
+var pokemonFormats = map[string]bool{
+	"standard":  true,
+	"expanded":  true,
+	"unlimited": true,
+}
+
+// validatePokemon checks a Pokémon deck against the deck construction rules
+func validatePokemon(entries []*DeckEntry, format string) ([]*Violation, error) {
+	if !pokemonFormats[format] {
+		return nil, fmt.Errorf("%w: %s is not a Pokémon format", ErrUnsupportedFormat, format)
+	}
+
+	var violations []*Violation
+
+	if total := countCards(entries); total != pokemonDeckSize {
+		violations = append(violations, &Violation{
+			Code:    CodeDeckSize,
+			Message: fmt.Sprintf("deck must contain exactly %d cards, found %d", pokemonDeckSize, total),
+		})
+	}
+
+	for _, entry := range entries {
+		if !entry.HasDetails {
+			violations = append(violations, cardViolation(CodeMissingCardData, entry,
+				fmt.Sprintf("%s has no Pokémon card data and cannot be checked", entry.Name)))
+		}
+	}
+
+	// Copy limits apply per card name, and basic Energy may be played in any quantity
+	for _, group := range copiesByName(entries) {
+		if isBasicEnergy(group.entry) {
+			continue
+		}
+		if group.quantity > pokemonMaxCopies {
+			violations = append(violations, cardViolation(CodeCopyLimit, group.entry,
+				fmt.Sprintf("deck may contain at most %d copies of %s, found %d", pokemonMaxCopies, group.entry.Name, group.quantity)))
+		}
+	}
+
+	var aceSpecs, radiants int
+	var lastAceSpec, lastRadiant *DeckEntry
+	hasBasicPokemon := false
+	for _, entry := range entries {
+		if hasSubtype(entry.Subtypes, "ACE SPEC") {
+			aceSpecs += entry.Quantity
+			lastAceSpec = entry
+		}
+		if hasSubtype(entry.Subtypes, "Radiant") {
+			radiants += entry.Quantity
+			lastRadiant = entry
+		}
+		if isPokemon(entry) && hasSubtype(entry.Subtypes, "Basic") {
+			hasBasicPokemon = true
+		}
+	}
+
+	if aceSpecs > 1 {
+		violations = append(violations, cardViolation(CodeAceSpec, lastAceSpec,
+			fmt.Sprintf("deck may contain only 1 ACE SPEC card, found %d", aceSpecs)))
+	}
+	if radiants > 1 {
+		violations = append(violations, cardViolation(CodeRadiant, lastRadiant,
+			fmt.Sprintf("deck may contain only 1 Radiant Pokémon, found %d", radiants)))
+	}
+	if !hasBasicPokemon {
+		violations = append(violations, &Violation{
+			Code:    CodeNoBasicPokemon,
+			Message: "deck must contain at least 1 Basic Pokémon",
+		})
+	}
+
+	return violations, nil
+}
+
+// isPokemon reports whether the entry is a Pokémon card rather than a Trainer or Energy
+func isPokemon(entry *DeckEntry) bool {
+	return strings.HasPrefix(strings.ToLower(entry.Supertype), "pok")
+}
+
+// isBasicEnergy reports whether the entry is a basic Energy card, which is exempt from copy limits
+func isBasicEnergy(entry *DeckEntry) bool {
+	return strings.EqualFold(entry.Supertype, "Energy") && hasSubtype(entry.Subtypes, "Basic")
+}
@@ -0,0 +1,263 @@
+package validation
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/shiftregister-vg/card-craft/internal/models"
+)
+
+// Violation codes reported by the deck validators
+const (
+	CodeDeckSize        = "DECK_SIZE"
+	CodeSideboardSize   = "SIDEBOARD_SIZE"
+	CodeCopyLimit       = "COPY_LIMIT"
+	CodeAceSpec         = "ACE_SPEC_LIMIT"
+	CodeRadiant         = "RADIANT_LIMIT"
+	CodeNoBasicPokemon  = "NO_BASIC_POKEMON"
+	CodeNotLegal        = "NOT_LEGAL"
+	CodeBanned          = "BANNED"
+	CodeRestricted      = "RESTRICTED"
+	CodeColorIdentity   = "COLOR_IDENTITY"
+	CodeCommander       = "COMMANDER"
+	CodeMissingCardData = "MISSING_CARD_DATA"
+	CodeUnsupportedGame = "UNSUPPORTED_GAME"
+)
+
+// ErrUnsupportedFormat is returned when a deck is validated against a format the game does not have
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+// Violation describes a single rule a deck breaks
+type Violation struct {
+	Code     string  `json:"code"`
+	Message  string  `json:"message"`
+	CardID   *string `json:"cardId"`
+	CardName *string `json:"cardName"`
+}
+
+// Result is the outcome of validating a deck against a format
+type Result struct {
+	DeckID     string       `json:"deckId"`
+	Game       string       `json:"game"`
+	Format     string       `json:"format"`
+	Legal      bool         `json:"legal"`
+	CardCount  int          `json:"cardCount"`
+	Violations []*Violation `json:"violations"`
+}
+
+// DeckEntry is a deck card together with the game data the rules need
+type DeckEntry struct {
+	CardID   uuid.UUID
+	Name     string
+	Quantity int
+	Zone     string
+
+	// Pokémon details
+	Supertype string
+	Subtypes  []string
+
+	// MTG details
+	TypeLine      string
+	OracleText    string
+	ColorIdentity []string
+	Legalities    map[string]string
+
+	// HasDetails reports whether the game-specific row exists for this card
+	HasDetails bool
+}
+
+// Service validates decks stored in the database
+type Service struct {
+	db *sql.DB
+}
+
+// NewService creates a new validation service
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+// ValidateDeck loads the cards of a deck and checks them against the rules of the given format
+func (s *Service) ValidateDeck(ctx context.Context, deck *models.Deck, format string) (*Result, error) {
+	entries, err := s.loadEntries(ctx, deck)
+	if err != nil {
+		return nil, err
+	}
+
+	return Validate(deck, entries, format)
+}
+
+// Validate checks deck entries against the rules of the given format. Cards in the
+// maybeboard are not part of the deck and are ignored.
+func Validate(deck *models.Deck, entries []*DeckEntry, format string) (*Result, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+
+	var playable []*DeckEntry
+	for _, entry := range entries {
+		if entry.Zone != models.ZoneMaybeboard {
+			playable = append(playable, entry)
+		}
+	}
+	entries = playable
+
+	var violations []*Violation
+	var err error
+	switch strings.ToLower(deck.Game) {
+	case "pokemon":
+		if format == "" {
+			format = defaultPokemonFormat
+		}
+		violations, err = validatePokemon(entries, format)
+	case "mtg":
+		violations, err = validateMTG(entries, format)
+	default:
+		violations = []*Violation{{
+			Code:    CodeUnsupportedGame,
+			Message: fmt.Sprintf("deck validation is not available for %s", deck.Game),
+		}}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if violations == nil {
+		violations = []*Violation{}
+	}
+
+	return &Result{
+		DeckID:     deck.ID.String(),
+		Game:       deck.Game,
+		Format:     format,
+		Legal:      len(violations) == 0,
+		CardCount:  countCards(inZones(entries, models.ZoneMain, models.ZoneCommander)),
+		Violations: violations,
+	}, nil
+}
+
+// loadEntries fetches the deck cards joined with their base and game-specific data
+func (s *Service) loadEntries(ctx context.Context, deck *models.Deck) ([]*DeckEntry, error) {
+	query := `
+		SELECT dc.card_id, c.name, dc.quantity, dc.zone,
+			p.id IS NOT NULL OR m.id IS NOT NULL,
+			COALESCE(p.supertype, ''), p.subtypes,
+			COALESCE(m.type_line, ''), COALESCE(m.oracle_text, ''), m.color_identity, m.legalities
+		FROM deck_cards dc
+		JOIN cards c ON c.id = dc.card_id
+		LEFT JOIN pokemon_cards p ON p.card_id = c.id
+		LEFT JOIN mtg_cards m ON m.card_id = c.id
+		WHERE dc.deck_id = $1
+		ORDER BY c.name, dc.zone
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, deck.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load deck cards: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*DeckEntry
+	for rows.Next() {
+		entry := &DeckEntry{}
+		var legalitiesJSON []byte
+		err := rows.Scan(
+			&entry.CardID,
+			&entry.Name,
+			&entry.Quantity,
+			&entry.Zone,
+			&entry.HasDetails,
+			&entry.Supertype,
+			pq.Array(&entry.Subtypes),
+			&entry.TypeLine,
+			&entry.OracleText,
+			pq.Array(&entry.ColorIdentity),
+			&legalitiesJSON,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan deck card: %w", err)
+		}
+
+		if legalitiesJSON != nil {
+			if err := json.Unmarshal(legalitiesJSON, &entry.Legalities); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal legalities: %w", err)
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// countCards returns the total number of cards across all entries
+func countCards(entries []*DeckEntry) int {
+	total := 0
+	for _, entry := range entries {
+		total += entry.Quantity
+	}
+	return total
+}
+
+// inZones returns the entries placed in any of the given zones
+func inZones(entries []*DeckEntry, zones ...string) []*DeckEntry {
+	var matched []*DeckEntry
+	for _, entry := range entries {
+		for _, zone := range zones {
+			if entry.Zone == zone {
+				matched = append(matched, entry)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// nameGroup is the combined quantity of every printing of a card name in a deck
+type nameGroup struct {
+	entry    *DeckEntry
+	quantity int
+}
+
+// copiesByName groups entry quantities by card name, since reprints and copies in other zones
+// count towards the same limit
+func copiesByName(entries []*DeckEntry) []*nameGroup {
+	byName := make(map[string]*nameGroup)
+	var groups []*nameGroup
+	for _, entry := range entries {
+		key := strings.ToLower(entry.Name)
+		group, ok := byName[key]
+		if !ok {
+			group = &nameGroup{entry: entry}
+			byName[key] = group
+			groups = append(groups, group)
+		}
+		group.quantity += entry.Quantity
+	}
+	return groups
+}
+
+// cardViolation creates a violation tied to a specific card
+func cardViolation(code string, entry *DeckEntry, message string) *Violation {
+	cardID := entry.CardID.String()
+	cardName := entry.Name
+	return &Violation{
+		Code:     code,
+		Message:  message,
+		CardID:   &cardID,
+		CardName: &cardName,
+	}
+}
+
+// hasSubtype reports whether the subtypes contain the given value, ignoring case
+func hasSubtype(subtypes []string, subtype string) bool {
+	for _, s := range subtypes {
+		if strings.EqualFold(s, subtype) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_deck_cards_id;
+
+ALTER TABLE deck_cards DROP COLUMN IF EXISTS id;
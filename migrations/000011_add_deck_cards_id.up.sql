@@ -0,0 +1,4 @@
+-- Give deck_cards rows their own identifier so they can be addressed directly
+ALTER TABLE deck_cards ADD COLUMN id UUID NOT NULL DEFAULT gen_random_uuid();
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_deck_cards_id ON deck_cards(id);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS lorcana_cards;
@@ -0,0 +1,27 @@
+CREATE TABLE lorcana_cards (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    card_id UUID NOT NULL UNIQUE REFERENCES cards(id) ON DELETE CASCADE,
+    version TEXT,
+    ink_color TEXT NOT NULL,
+    inkable BOOLEAN NOT NULL DEFAULT false,
+    card_type TEXT NOT NULL,
+    cost INTEGER NOT NULL,
+    strength INTEGER,
+    willpower INTEGER,
+    lore INTEGER,
+    move_cost INTEGER,
+    classifications TEXT[],
+    abilities JSONB,
+    body_text TEXT,
+    flavor_text TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX lorcana_cards_ink_color_idx ON lorcana_cards(ink_color);
+
+-- Create trigger to automatically update updated_at
+CREATE TRIGGER update_lorcana_cards_updated_at
+    BEFORE UPDATE ON lorcana_cards
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
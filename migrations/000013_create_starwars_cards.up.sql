@@ -0,0 +1,30 @@
+CREATE TABLE starwars_cards (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    card_id UUID NOT NULL UNIQUE REFERENCES cards(id) ON DELETE CASCADE,
+    subtitle TEXT,
+    card_type TEXT NOT NULL,
+    aspects TEXT[],
+    arenas TEXT[],
+    traits TEXT[],
+    keywords TEXT[],
+    cost INTEGER,
+    power INTEGER,
+    hp INTEGER,
+    is_unique BOOLEAN NOT NULL DEFAULT false,
+    front_text TEXT,
+    epic_action TEXT,
+    double_sided BOOLEAN NOT NULL DEFAULT false,
+    back_text TEXT,
+    back_image_url TEXT,
+    variant_type TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX starwars_cards_card_type_idx ON starwars_cards(card_type);
+
+-- Create trigger to automatically update updated_at
+CREATE TRIGGER update_starwars_cards_updated_at
+    BEFORE UPDATE ON starwars_cards
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
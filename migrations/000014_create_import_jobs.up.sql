@@ -0,0 +1,28 @@
+CREATE TABLE import_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    game TEXT NOT NULL,
+    trigger TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'running',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    cards_processed INTEGER NOT NULL DEFAULT 0,
+    cards_inserted INTEGER NOT NULL DEFAULT 0,
+    cards_updated INTEGER NOT NULL DEFAULT 0,
+    cards_skipped INTEGER NOT NULL DEFAULT 0,
+    error_count INTEGER NOT NULL DEFAULT 0,
+    error_message TEXT,
+    error_samples TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT import_jobs_trigger_check CHECK (trigger IN ('scheduler', 'cli', 'api')),
+    CONSTRAINT import_jobs_status_check CHECK (status IN ('running', 'succeeded', 'failed'))
+);
+
+CREATE INDEX import_jobs_started_at_idx ON import_jobs(started_at DESC);
+CREATE INDEX import_jobs_game_started_at_idx ON import_jobs(game, started_at DESC);
+
+-- Create trigger to automatically update updated_at
+CREATE TRIGGER update_import_jobs_updated_at
+    BEFORE UPDATE ON import_jobs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
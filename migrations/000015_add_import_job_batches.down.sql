@@ -0,0 +1,3 @@
+ALTER TABLE import_jobs
+    DROP COLUMN IF EXISTS batches_total,
+    DROP COLUMN IF EXISTS batches_processed;
@@ -0,0 +1,3 @@
+ALTER TABLE import_jobs
+    ADD COLUMN batches_processed INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN batches_total INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+UPDATE import_jobs SET status = 'failed' WHERE status = 'cancelled';
+
+ALTER TABLE import_jobs
+    DROP COLUMN IF EXISTS cancel_requested,
+    DROP CONSTRAINT import_jobs_status_check,
+    ADD CONSTRAINT import_jobs_status_check CHECK (status IN ('running', 'succeeded', 'failed'));
+
+DROP TABLE IF EXISTS import_checkpoints;
@@ -0,0 +1,18 @@
+CREATE TABLE import_checkpoints (
+    game TEXT PRIMARY KEY,
+    source TEXT NOT NULL,
+    batches_completed INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create trigger to automatically update updated_at
+CREATE TRIGGER update_import_checkpoints_updated_at
+    BEFORE UPDATE ON import_checkpoints
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE import_jobs
+    ADD COLUMN cancel_requested BOOLEAN NOT NULL DEFAULT false,
+    DROP CONSTRAINT import_jobs_status_check,
+    ADD CONSTRAINT import_jobs_status_check CHECK (status IN ('running', 'succeeded', 'failed', 'cancelled'));
@@ -0,0 +1,14 @@
+CREATE TABLE import_set_syncs (
+    game TEXT NOT NULL,
+    set_code TEXT NOT NULL,
+    source_updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (game, set_code)
+);
+
+-- Create trigger to automatically update updated_at
+CREATE TRIGGER update_import_set_syncs_updated_at
+    BEFORE UPDATE ON import_set_syncs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
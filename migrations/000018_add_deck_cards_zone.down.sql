@@ -0,0 +1,7 @@
+-- Cards outside the main deck cannot be represented without zones
+DELETE FROM deck_cards WHERE zone <> 'main';
+
+ALTER TABLE deck_cards DROP CONSTRAINT deck_cards_pkey;
+ALTER TABLE deck_cards ADD PRIMARY KEY (deck_id, card_id);
+
+ALTER TABLE deck_cards DROP COLUMN IF EXISTS zone;
@@ -0,0 +1,5 @@
+-- Let a card appear in several zones of a deck, such as the main deck and the sideboard
+ALTER TABLE deck_cards ADD COLUMN zone TEXT NOT NULL DEFAULT 'main';
+
+ALTER TABLE deck_cards DROP CONSTRAINT deck_cards_pkey;
+ALTER TABLE deck_cards ADD PRIMARY KEY (deck_id, card_id, zone);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS deck_revision_cards;
+DROP TABLE IF EXISTS deck_revisions;
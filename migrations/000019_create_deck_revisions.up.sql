@@ -0,0 +1,27 @@
+-- Immutable snapshots of a deck's card list
+CREATE TABLE deck_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    deck_id UUID NOT NULL REFERENCES decks(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (deck_id, number)
+);
+
+CREATE TABLE deck_revision_cards (
+    revision_id UUID NOT NULL REFERENCES deck_revisions(id) ON DELETE CASCADE,
+    card_id UUID NOT NULL REFERENCES cards(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    zone TEXT NOT NULL DEFAULT 'main',
+    PRIMARY KEY (revision_id, card_id, zone)
+);
+
+-- Start the history of existing decks from their current card list
+INSERT INTO deck_revisions (deck_id, number, message, created_at)
+SELECT id, 1, 'Initial revision', updated_at
+FROM decks;
+
+INSERT INTO deck_revision_cards (revision_id, card_id, quantity, zone)
+SELECT r.id, dc.card_id, dc.quantity, dc.zone
+FROM deck_revisions r
+JOIN deck_cards dc ON dc.deck_id = r.deck_id;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_decks_public;
+DROP INDEX IF EXISTS idx_decks_slug;
+
+ALTER TABLE decks DROP COLUMN IF EXISTS slug;
+
+ALTER TABLE decks ADD COLUMN is_public BOOLEAN NOT NULL DEFAULT false;
+UPDATE decks SET is_public = visibility = 'public';
+
+ALTER TABLE decks DROP COLUMN IF EXISTS visibility;
@@ -0,0 +1,19 @@
+-- Replace is_public with a visibility that also allows unlisted decks, reachable only by link
+ALTER TABLE decks ADD COLUMN visibility TEXT NOT NULL DEFAULT 'private'
+    CHECK (visibility IN ('private', 'unlisted', 'public'));
+
+UPDATE decks SET visibility = 'public' WHERE is_public;
+
+ALTER TABLE decks DROP COLUMN is_public;
+
+-- Stable slugs for share links, built from the deck name and a random suffix
+ALTER TABLE decks ADD COLUMN slug TEXT;
+
+UPDATE decks SET slug = concat_ws('-',
+    NULLIF(left(trim(BOTH '-' FROM lower(regexp_replace(name, '[^a-zA-Z0-9]+', '-', 'g'))), 60), ''),
+    substr(md5(id::text), 1, 8));
+
+ALTER TABLE decks ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX idx_decks_slug ON decks(slug);
+CREATE INDEX idx_decks_public ON decks(game, updated_at DESC, id DESC) WHERE visibility = 'public';
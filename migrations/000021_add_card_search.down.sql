@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_cards_name_trgm;
+DROP INDEX IF EXISTS idx_cards_search_vector;
+
+DROP TRIGGER IF EXISTS refresh_starwars_cards_search_text ON starwars_cards;
+DROP TRIGGER IF EXISTS refresh_lorcana_cards_search_text ON lorcana_cards;
+DROP TRIGGER IF EXISTS refresh_pokemon_cards_search_text ON pokemon_cards;
+DROP TRIGGER IF EXISTS refresh_mtg_cards_search_text ON mtg_cards;
+
+DROP FUNCTION IF EXISTS refresh_card_search_text();
+DROP FUNCTION IF EXISTS card_search_text(UUID);
+
+ALTER TABLE cards DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE cards DROP COLUMN IF EXISTS search_text;
@@ -0,0 +1,90 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- search_text collects the rules text of a card from its game-specific details, so it can be
+-- searched together with the name
+ALTER TABLE cards ADD COLUMN search_text TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE cards ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(search_text, '')), 'B')
+) STORED;
+
+-- Builds the searchable text of a card from whichever game details it has
+CREATE OR REPLACE FUNCTION card_search_text(p_card_id UUID)
+RETURNS TEXT AS $$
+    SELECT concat_ws(' ',
+        (SELECT string_agg(concat_ws(' ', type_line, oracle_text, array_to_string(keywords, ' ')), ' ')
+            FROM mtg_cards WHERE card_id = p_card_id),
+        (SELECT string_agg(concat_ws(' ',
+                supertype,
+                array_to_string(subtypes, ' '),
+                array_to_string(rules, ' '),
+                (SELECT string_agg(concat_ws(' ', a->>'name', a->>'text'), ' ')
+                    FROM jsonb_array_elements(CASE WHEN jsonb_typeof(p.abilities) = 'array' THEN p.abilities ELSE '[]' END) a),
+                (SELECT string_agg(concat_ws(' ', a->>'name', a->>'text'), ' ')
+                    FROM jsonb_array_elements(CASE WHEN jsonb_typeof(p.attacks) = 'array' THEN p.attacks ELSE '[]' END) a)
+            ), ' ')
+            FROM pokemon_cards p WHERE card_id = p_card_id),
+        (SELECT string_agg(concat_ws(' ',
+                version,
+                card_type,
+                array_to_string(classifications, ' '),
+                body_text,
+                (SELECT string_agg(a->>'fullText', ' ')
+                    FROM jsonb_array_elements(CASE WHEN jsonb_typeof(l.abilities) = 'array' THEN l.abilities ELSE '[]' END) a)
+            ), ' ')
+            FROM lorcana_cards l WHERE card_id = p_card_id),
+        (SELECT string_agg(concat_ws(' ',
+                subtitle,
+                card_type,
+                array_to_string(traits, ' '),
+                array_to_string(keywords, ' '),
+                front_text,
+                epic_action,
+                back_text
+            ), ' ')
+            FROM starwars_cards WHERE card_id = p_card_id)
+    )
+$$ LANGUAGE sql STABLE;
+
+-- Keeps cards.search_text in step with the game details
+CREATE OR REPLACE FUNCTION refresh_card_search_text()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE cards SET search_text = card_search_text(OLD.card_id) WHERE id = OLD.card_id;
+        RETURN OLD;
+    END IF;
+
+    UPDATE cards SET search_text = card_search_text(NEW.card_id) WHERE id = NEW.card_id;
+    IF TG_OP = 'UPDATE' AND OLD.card_id <> NEW.card_id THEN
+        UPDATE cards SET search_text = card_search_text(OLD.card_id) WHERE id = OLD.card_id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER refresh_mtg_cards_search_text
+    AFTER INSERT OR UPDATE OR DELETE ON mtg_cards
+    FOR EACH ROW
+    EXECUTE FUNCTION refresh_card_search_text();
+
+CREATE TRIGGER refresh_pokemon_cards_search_text
+    AFTER INSERT OR UPDATE OR DELETE ON pokemon_cards
+    FOR EACH ROW
+    EXECUTE FUNCTION refresh_card_search_text();
+
+CREATE TRIGGER refresh_lorcana_cards_search_text
+    AFTER INSERT OR UPDATE OR DELETE ON lorcana_cards
+    FOR EACH ROW
+    EXECUTE FUNCTION refresh_card_search_text();
+
+CREATE TRIGGER refresh_starwars_cards_search_text
+    AFTER INSERT OR UPDATE OR DELETE ON starwars_cards
+    FOR EACH ROW
+    EXECUTE FUNCTION refresh_card_search_text();
+
+UPDATE cards SET search_text = card_search_text(id);
+
+CREATE INDEX idx_cards_search_vector ON cards USING GIN (search_vector);
+CREATE INDEX idx_cards_name_trgm ON cards USING GIN (name gin_trgm_ops);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_cards_game_price;
+DROP INDEX IF EXISTS idx_cards_game_released_at;
+DROP INDEX IF EXISTS idx_cards_game_name;
+
+ALTER TABLE cards DROP COLUMN IF EXISTS price_usd;
+ALTER TABLE cards DROP COLUMN IF EXISTS released_at;
@@ -0,0 +1,12 @@
+-- Release date and market price, so card searches can be sorted by them. Both stay NULL for
+-- cards whose source does not provide them.
+ALTER TABLE cards ADD COLUMN released_at DATE;
+ALTER TABLE cards ADD COLUMN price_usd NUMERIC(10, 2);
+
+UPDATE cards c SET released_at = m.released_at
+FROM mtg_cards m
+WHERE m.card_id = c.id;
+
+CREATE INDEX idx_cards_game_name ON cards(game, name, id);
+CREATE INDEX idx_cards_game_released_at ON cards(game, released_at, id);
+CREATE INDEX idx_cards_game_price ON cards(game, price_usd, id);
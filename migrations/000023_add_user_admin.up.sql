@@ -0,0 +1,3 @@
+-- Admins may run and cancel card imports through the API. Grant it with
+-- UPDATE users SET is_admin = true WHERE email = '...';
+ALTER TABLE users ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,2 @@
+-- The user who started an import through the API, who may cancel it
+ALTER TABLE import_jobs ADD COLUMN started_by UUID REFERENCES users(id) ON DELETE SET NULL;
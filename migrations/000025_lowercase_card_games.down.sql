@@ -0,0 +1,2 @@
+-- The original case of the games is not kept, and lower case games stay valid
+SELECT 1;
@@ -0,0 +1,3 @@
+-- Card games are compared with = so searches can use the indexes on (game, ...). The
+-- importers already write them in lower case; this catches cards written any other way.
+UPDATE cards SET game = LOWER(game) WHERE game <> LOWER(game);